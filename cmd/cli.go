@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/config"
+	"kong-anime-go/internal/dao"
+	"kong-anime-go/internal/metadata/bangumi"
+	animesrv "kong-anime-go/internal/services/anime"
+	authsrv "kong-anime-go/internal/services/auth"
+	backupsrv "kong-anime-go/internal/services/backup"
+	followsrv "kong-anime-go/internal/services/follow"
+	importersrv "kong-anime-go/internal/services/importer"
+
+	"golang.org/x/term"
+)
+
+// usage 命令行用法
+const usage = `用法:
+  kong-anime-go                                      启动服务器
+  kong-anime-go import-csv [-dry-run] [-force] [-tsv] <file>
+                                                     从 CSV/TSV 导入动漫目录
+  kong-anime-go backup <file>                        备份整个数据库到 tar.gz 文件
+  kong-anime-go restore <file>                       从备份恢复到空数据库（保留已有用户）
+  kong-anime-go metadata-stub [-addr :8090] <file>   用 JSON 文件中的条目启动 Bangumi API 替身服务器
+  kong-anime-go create-user [-name <显示名称>] [-role viewer|editor|admin] <username>
+                                                     创建用户，密码在终端中输入（不回显）或从标准输入读取第一行，
+                                                     第一个用户为管理员并认领已有的追番
+`
+
+// runCommand 执行命令行子命令，返回进程退出码
+func runCommand(args []string) int {
+	switch args[0] {
+	case "import-csv":
+		return importCSV(args[1:])
+	case "backup":
+		return backupDB(args[1:])
+	case "restore":
+		return restoreDB(args[1:])
+	case "metadata-stub":
+		return metadataStub(args[1:])
+	case "create-user":
+		return createUser(args[1:])
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+}
+
+// importCSV 从 CSV/TSV 导入动漫目录，校验失败时不写入任何数据
+func importCSV(args []string) int {
+	fs := flag.NewFlagSet("import-csv", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "只校验，不写入数据")
+	force := fs.Bool("force", false, "跳过与已有动漫的重复检查")
+	tsv := fs.Bool("tsv", false, "文件为 TSV 格式（默认根据扩展名判断）")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+	fileName := fs.Arg(0)
+
+	file, err := os.Open(fileName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer file.Close()
+
+	delimiter := ','
+	if *tsv || strings.HasSuffix(strings.ToLower(fileName), ".tsv") {
+		delimiter = '\t'
+	}
+
+	db := dao.InitDB()
+	animeDAO := dao.NewAnimeDAO(db)
+	followDAO := dao.NewFollowDAO(db)
+	animeSrv := animesrv.NewService(animeDAO, dao.NewCategoryDAO(db), dao.NewTagDAO(db), followDAO, dao.NewStudioDAO(db))
+	followSrv := followsrv.NewService(followDAO, animeDAO, dao.NewFollowCategoryDAO(db))
+	importerSrv := importersrv.NewService(dao.NewImportDAO(db), animeDAO, animeSrv, followSrv)
+
+	job, err := importerSrv.ImportCatalogue(0, fileName, file, delimiter, *dryRun, *force)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	for _, row := range job.Rows {
+		fmt.Printf("line %d\t%s\t%s\t%s\n", row.Line, row.Action, row.Title, row.Message)
+	}
+	fmt.Printf("job %d %s: created %d, skipped %d, failed %d\n", job.ID, job.Status, job.Created, job.Skipped, job.Failed)
+	if job.Status == importersrv.JobStatusFailed {
+		fmt.Fprintln(os.Stderr, job.Error)
+		return 1
+	}
+	return 0
+}
+
+// backupDB 备份整个数据库到文件，失败时删除不完整的文件
+func backupDB(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+	fileName := args[0]
+
+	file, err := os.Create(fileName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	db := dao.InitDB()
+	manifest, err := backupsrv.NewService(dao.NewBackupDAO(db)).Write(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(fileName)
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	printCounts(manifest)
+	fmt.Printf("backup written to %s (schema version %d)\n", fileName, manifest.SchemaVersion)
+	return 0
+}
+
+// restoreDB 从备份恢复到空数据库，已有的用户会保留
+func restoreDB(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer file.Close()
+
+	db := dao.InitDB()
+	manifest, err := backupsrv.NewService(dao.NewBackupDAO(db)).Restore(file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	printCounts(manifest)
+	fmt.Printf("restored backup created at %s (schema version %d)\n", manifest.CreatedAt.Format(time.DateTime), manifest.SchemaVersion)
+	return 0
+}
+
+func printCounts(manifest *backupsrv.Manifest) {
+	tables := make([]string, 0, len(manifest.Counts))
+	for table := range manifest.Counts {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		fmt.Printf("%s\t%d\n", table, manifest.Counts[table])
+	}
+}
+
+// metadataStub 启动 Bangumi API 替身服务器，用于测试和离线补全元数据
+func metadataStub(args []string) int {
+	fs := flag.NewFlagSet("metadata-stub", flag.ExitOnError)
+	addr := fs.String("addr", ":8090", "监听地址")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	var subjects []bangumi.Subject
+	if err := json.Unmarshal(data, &subjects); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid subjects file: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("serving %d subjects on %s\n", len(subjects), *addr)
+	if err := http.ListenAndServe(*addr, bangumi.NewStubServer(subjects)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// createUser 创建用户，未指定角色时第一个用户为管理员、其余为 viewer，第一个用户会认领引入用户之前创建的追番
+// 密码不作为参数传入，避免出现在进程列表和 shell 历史中
+func createUser(args []string) int {
+	fs := flag.NewFlagSet("create-user", flag.ExitOnError)
+	displayName := fs.String("name", "", "显示名称")
+	role := fs.String("role", "", "角色 (viewer、editor、admin)")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+	password, err := readPassword()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	db := dao.InitDB()
+	authSrv, err := authsrv.NewService(dao.NewUserDAO(db), config.Auth)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	user, claimed, err := authSrv.CreateUser(fs.Arg(0), password, *displayName, common.Role(*role))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Printf("user %d %s (%s) created\n", user.ID, user.Username, user.Role)
+	if claimed > 0 {
+		fmt.Printf("assigned %d existing follows to %s\n", claimed, user.Username)
+	}
+	return 0
+}
+
+// readPassword 标准输入是终端时提示输入两次密码（不回显），否则读取标准输入的第一行
+func readPassword() (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprint(os.Stderr, "Confirm password: ")
+	confirm, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	if string(password) != string(confirm) {
+		return "", errors.New("passwords do not match")
+	}
+	return string(password), nil
+}
@@ -15,9 +15,19 @@ import (
 )
 
 func main() {
-	config.InitConfig()          // 初始化配置
-	db := dao.InitDB()           // 初始化数据库连接
-	r := routers.SetupRouter(db) // 初始化路由
+	config.InitConfig() // 初始化配置
+
+	// 带参数时执行命令行子命令
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:]))
+	}
+
+	db := dao.InitDB() // 初始化数据库连接
+
+	// 后台任务的上下文，关闭服务器时取消
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	r := routers.SetupRouter(jobCtx, db) // 初始化路由
 	srv := &http.Server{
 		Addr:    ":8080",
 		Handler: r,
@@ -34,6 +44,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("Shutdown Server ...")
+	stopJobs()
 
 	// 上下文超时5秒
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -4,13 +4,17 @@ import (
 	"errors"
 	"net/http"
 	"strings"
-	"time"
 
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao"
 	"kong-anime-go/internal/dao/models"
+	"kong-anime-go/internal/i18n"
 	animesrv "kong-anime-go/internal/services/anime"
+	tagsrv "kong-anime-go/internal/services/tag"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // Handler 处理 Anime 相关的服务
@@ -25,48 +29,80 @@ func NewHandler(animeSrv *animesrv.Service) *Handler {
 	}
 }
 
-func formatSeason(season string) (string, error) {
-	formats := []string{"200601", "2006-01", "20061", "2006-1"}
-	for _, format := range formats {
-		t, err := time.Parse(format, season)
-		if err == nil {
-			return t.Format("2006-01"), nil
-		}
-	}
-	return "", errors.New("invalid season format")
-}
-
-func (api *Handler) bindAndValidateAnime(c *gin.Context, anime *models.Anime) ([]string, []string, error) {
+// bindAndValidateAnime 绑定并校验请求中的动漫，返回分类、标签和是否恢复自动推导放送状态
+func (api *Handler) bindAndValidateAnime(c *gin.Context, anime *models.Anime) ([]string, []string, bool, error) {
 	var req struct {
 		Name       string   `json:"name"`
 		Aliases    []string `json:"aliases"`
 		Categories []string `json:"categories"`
 		Tags       []string `json:"tags"`
 		Production string   `json:"production"`
+		Studios    []string `json:"studios"` // 联合制作时的制作公司列表，优先于 production
 		Season     string   `json:"season"`
-		Episodes   int      `json:"episodes"`
+		Episodes   *int     `json:"episodes"` // 集数未知时不传
 		Image      string   `json:"image"`
+
+		MediaType        common.MediaType     `json:"media_type"`         // 默认为 TV
+		AiringStatus     *common.AiringStatus `json:"airing_status"`      // 手动指定放送状态，为空时创建的动漫自动推导、更新的动漫保留原有设置
+		AiringStatusAuto bool                 `json:"airing_status_auto"` // 更新时清除手动指定的放送状态，恢复自动推导
+
+		ExternalIDs map[common.ExternalSource]string `json:"external_ids"` // 外部数据库中的ID，仅创建时使用
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		return nil, nil, err
+		return nil, nil, false, err
 	}
 
 	if req.Name == "" || req.Season == "" {
-		return nil, nil, errors.New("Name and Season are required")
+		return nil, nil, false, errors.New("Name and Season are required")
 	}
 
-	formattedSeason, err := formatSeason(req.Season)
+	formattedSeason, err := animesrv.FormatSeason(req.Season)
 	if err != nil {
-		return nil, nil, errors.New("Invalid season format")
+		return nil, nil, false, errors.New("Invalid season format")
+	}
+
+	episodes := common.EpisodesUnknown
+	if req.Episodes != nil {
+		if *req.Episodes < 1 {
+			return nil, nil, false, errors.New("episodes must be positive, omit it if unknown")
+		}
+		episodes = *req.Episodes
+	}
+	if err := animesrv.ValidateMediaType(req.MediaType, episodes); err != nil {
+		return nil, nil, false, err
+	}
+
+	if req.AiringStatus != nil && !req.AiringStatus.IsValid() {
+		return nil, nil, false, errors.New("Invalid airing status")
+	}
+	if req.AiringStatus != nil && req.AiringStatusAuto {
+		return nil, nil, false, errors.New("airing_status and airing_status_auto cannot be used together")
+	}
+
+	for source, externalID := range req.ExternalIDs {
+		if !source.IsValid() {
+			return nil, nil, false, errors.New("Invalid external source")
+		}
+		if externalID = strings.TrimSpace(externalID); externalID != "" {
+			anime.ExternalIDs = append(anime.ExternalIDs, models.ExternalID{Source: source, ExternalID: externalID})
+		}
 	}
 
 	anime.Name = req.Name
 	anime.Aliases = strings.Join(req.Aliases, ",")
 	anime.Production = req.Production
+	if len(req.Studios) > 0 {
+		anime.Production = strings.Join(req.Studios, "、")
+	}
 	anime.Season = formattedSeason
-	anime.Episodes = req.Episodes
+	anime.Episodes = episodes
 	anime.Image = req.Image
+	anime.MediaType = req.MediaType
+	if req.AiringStatus != nil {
+		anime.AiringStatus = *req.AiringStatus
+		anime.AiringStatusManual = true
+	}
 
 	if len(anime.Image) == 0 { // 如果没有传入图片，则使用 fakeimg.pl 生成图片
 		// 计算名字需要的图片尺寸
@@ -78,21 +114,32 @@ func (api *Handler) bindAndValidateAnime(c *gin.Context, anime *models.Anime) ([
 		anime.Image = "https://fakeimg.pl/" + strconv.Itoa(w) + "x" + strconv.Itoa(h) + "/?text=" + anime.Name + "&font=noto"
 	}
 
-	return req.Categories, req.Tags, nil
+	return req.Categories, req.Tags, req.AiringStatusAuto, nil
 }
 
-// Create 创建一个新的动漫
+// Create 创建一个新的动漫，存在可能重复的动漫时返回 409 和候选列表，force=true 时跳过检查
 func (api *Handler) Create(c *gin.Context) {
+	force, _ := strconv.ParseBool(c.DefaultQuery("force", "false"))
 	anime := &models.Anime{}
-	categories, tags, err := api.bindAndValidateAnime(c, anime)
+	categories, tags, _, err := api.bindAndValidateAnime(c, anime)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 
-	anime, err = api.AnimeSrv.Create(anime, categories, tags)
+	anime, err = api.AnimeSrv.Create(anime, categories, tags, force)
+	var groupViolation *tagsrv.ErrGroupViolation
+	if errors.As(err, &groupViolation) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err), "violations": groupViolation.Violations})
+		return
+	}
+	var duplicate *animesrv.ErrPossibleDuplicate
+	if errors.As(err, &duplicate) {
+		c.JSON(http.StatusConflict, gin.H{"error": i18n.Message(c, "Possible duplicate anime, use force=true to create anyway"), "duplicates": duplicate.Candidates})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 
@@ -105,7 +152,7 @@ func (api *Handler) Delete(c *gin.Context) {
 	force, _ := strconv.ParseBool(c.DefaultQuery("force", "false"))
 
 	if _, err := api.AnimeSrv.Delete(uint(id), force); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "id": id})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err), "id": id})
 		return
 	}
 
@@ -118,15 +165,20 @@ func (api *Handler) Update(c *gin.Context) {
 
 	anime := &models.Anime{}
 	anime.ID = uint(id)
-	categories, tags, err := api.bindAndValidateAnime(c, anime)
+	categories, tags, autoAiringStatus, err := api.bindAndValidateAnime(c, anime)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 
-	anime, err = api.AnimeSrv.Update(anime, categories, tags)
+	anime, err = api.AnimeSrv.Update(anime, categories, tags, autoAiringStatus)
+	var groupViolation *tagsrv.ErrGroupViolation
+	if errors.As(err, &groupViolation) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err), "violations": groupViolation.Violations})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 
@@ -139,7 +191,7 @@ func (api *Handler) GetByID(c *gin.Context) {
 
 	anime, err := api.AnimeSrv.GetByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Anime not found"})
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.Message(c, "Anime not found")})
 		return
 	}
 
@@ -151,13 +203,56 @@ func (api *Handler) GetAll(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
 
-	animes, total, err := api.AnimeSrv.GetAll(page, pageSize)
+	var filter dao.AnimeFilter
+	if airingStatusStr := c.Query("airing_status"); airingStatusStr != "" {
+		airingStatusVal, err := strconv.Atoi(airingStatusStr)
+		airingStatus := common.AiringStatus(airingStatusVal)
+		if err != nil || !airingStatus.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid airing status")})
+			return
+		}
+		filter.AiringStatus = &airingStatus
+	}
+	if mediaTypeStr := c.Query("media_type"); mediaTypeStr != "" {
+		mediaTypeVal, err := strconv.Atoi(mediaTypeStr)
+		mediaType := common.MediaType(mediaTypeVal)
+		if err != nil || !mediaType.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid media type")})
+			return
+		}
+		filter.MediaType = &mediaType
+	}
+	if personIDStr := c.Query("person_id"); personIDStr != "" {
+		personID, err := strconv.Atoi(personIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid person ID")})
+			return
+		}
+		personIDVal := uint(personID)
+		filter.PersonID = &personIDVal
+	}
+	if roleStr := c.Query("role"); roleStr != "" {
+		roleVal, err := strconv.Atoi(roleStr)
+		role := common.CreditRole(roleVal)
+		if err != nil || !role.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid role")})
+			return
+		}
+		filter.CreditRole = &role
+	}
+
+	animes, total, err := api.AnimeSrv.GetAll(page, pageSize, filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	mediaTypes, err := api.AnimeSrv.GetMediaTypeCounts(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"animes": animes, "total": total, "page": page, "pageSize": pageSize})
+	c.JSON(http.StatusOK, gin.H{"animes": animes, "total": total, "page": page, "pageSize": pageSize, "media_types": mediaTypes})
 }
 
 // GetByName 根据名称获取动漫
@@ -168,7 +263,7 @@ func (api *Handler) GetByName(c *gin.Context) {
 
 	animes, total, err := api.AnimeSrv.GetByName(name, page, pageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 
@@ -178,9 +273,9 @@ func (api *Handler) GetByName(c *gin.Context) {
 // GetBySeason 根据季节获取动漫
 func (api *Handler) GetBySeason(c *gin.Context) {
 	season := c.Query("season")
-	formattedSeason, err := formatSeason(season)
+	formattedSeason, err := animesrv.FormatSeason(season)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season format"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid season format")})
 		return
 	}
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
@@ -188,11 +283,17 @@ func (api *Handler) GetBySeason(c *gin.Context) {
 
 	animes, total, err := api.AnimeSrv.GetBySeason(formattedSeason, page, pageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"animes": animes, "total": total, "page": page, "pageSize": pageSize})
+	c.JSON(http.StatusOK, gin.H{
+		"animes":      animes,
+		"total":       total,
+		"page":        page,
+		"pageSize":    pageSize,
+		"season_name": i18n.SeasonName(i18n.FromContext(c), formattedSeason),
+	})
 }
 
 // GetByCategory 根据分类获取动漫
@@ -203,7 +304,7 @@ func (api *Handler) GetByCategory(c *gin.Context) {
 
 	animes, total, err := api.AnimeSrv.GetByCategory(categoryName, page, pageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 
@@ -218,7 +319,7 @@ func (api *Handler) GetByTag(c *gin.Context) {
 
 	animes, total, err := api.AnimeSrv.GetByTag(tagName, page, pageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 
@@ -234,13 +335,13 @@ func (api *Handler) AddCategoriesToAnime(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 
 	anime, err := api.AnimeSrv.AddCategoriesToAnime(uint(id), req.Categories)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 
@@ -256,13 +357,22 @@ func (api *Handler) AddTagsToAnime(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 
 	anime, err := api.AnimeSrv.AddTagsToAnime(uint(id), req.Tags)
+	var groupViolation *tagsrv.ErrGroupViolation
+	if errors.As(err, &groupViolation) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err), "violations": groupViolation.Violations})
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 
@@ -273,9 +383,198 @@ func (api *Handler) AddTagsToAnime(c *gin.Context) {
 func (api *Handler) GetAllSeasons(c *gin.Context) {
 	seasons, err := api.AnimeSrv.GetAllSeasons()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	airingStatuses, err := api.AnimeSrv.GetSeasonAiringStatusCounts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+
+	mediaTypes, err := api.AnimeSrv.GetSeasonMediaTypeCounts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+
+	// 季度的显示名称按请求的语言生成，键为 2024-01 格式的季度
+	lang := i18n.FromContext(c)
+	seasonNames := make(map[string]string)
+	for year, months := range seasons {
+		for month := range months {
+			season := year + "-" + month
+			seasonNames[season] = i18n.SeasonName(lang, season)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"seasons":         seasons,
+		"season_names":    seasonNames,
+		"airing_statuses": airingStatuses,
+		"media_types":     mediaTypes,
+	})
+}
+
+// GetByExternalID 根据外部数据库中的ID获取动漫
+func (api *Handler) GetByExternalID(c *gin.Context) {
+	source := common.ExternalSource(c.Param("source"))
+	anime, err := api.AnimeSrv.GetByExternalID(source, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	if anime == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.Message(c, "Anime not found")})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"anime": anime})
+}
+
+// AddExternalID 为动漫添加外部数据库中的ID
+func (api *Handler) AddExternalID(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+
+	var req struct {
+		Source     common.ExternalSource `json:"source"`
+		ExternalID string                `json:"external_id"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+
+	anime, err := api.AnimeSrv.AddExternalID(uint(id), req.Source, req.ExternalID)
+	if errors.Is(err, animesrv.ErrExternalIDTaken) {
+		c.JSON(http.StatusConflict, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"seasons": seasons})
+	c.JSON(http.StatusOK, gin.H{"msg": "External ID added successfully!", "anime": anime})
+}
+
+// DeleteExternalIDs 删除动漫在指定外部数据库中的ID
+func (api *Handler) DeleteExternalIDs(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	source := common.ExternalSource(c.Param("source"))
+
+	anime, err := api.AnimeSrv.DeleteExternalIDs(uint(id), source)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"msg": "External IDs deleted successfully!", "anime": anime})
+}
+
+// SetLockedFields 设置动漫被锁定的字段，被锁定的字段不会被元数据补全、导入等自动写入修改
+func (api *Handler) SetLockedFields(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid ID")})
+		return
+	}
+	var req struct {
+		Fields []string `json:"fields"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	if err := animesrv.ValidateFields(req.Fields); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err), "fields": animesrv.Fields})
+		return
+	}
+
+	anime, err := api.AnimeSrv.SetLockedFields(uint(id), req.Fields)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"msg": "Locked fields updated!", "locked_fields": animesrv.LockedFields(anime), "anime": anime})
+}
+
+// GetDuplicates 列出整个目录中可能重复的动漫分组
+func (api *Handler) GetDuplicates(c *gin.Context) {
+	clusters, err := api.AnimeSrv.GetDuplicateClusters()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"clusters": clusters, "threshold": animesrv.DuplicateThreshold})
+}
+
+// GetTagGroupViolations 列出标签不符合标签组规则的动漫
+func (api *Handler) GetTagGroupViolations(c *gin.Context) {
+	violations, err := api.AnimeSrv.GetTagGroupViolations()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"animes": violations, "total": len(violations)})
+}
+
+// Merge 将 source_id 指定的动漫合并到当前动漫，源动漫合并后被删除
+func (api *Handler) Merge(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid ID")})
+		return
+	}
+	var req struct {
+		SourceID       uint   `json:"source_id" binding:"required"`
+		FollowStrategy string `json:"follow_strategy"` // 两者都有追番时保留哪个：target（默认）、source、latest
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	if req.FollowStrategy != "" && !animesrv.ValidFollowStrategy(req.FollowStrategy) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid follow strategy")})
+		return
+	}
+	if req.SourceID == uint(id) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Cannot merge anime into itself")})
+		return
+	}
+
+	anime, merge, skipped, err := api.AnimeSrv.Merge(uint(id), req.SourceID, req.FollowStrategy)
+	var groupViolation *tagsrv.ErrGroupViolation
+	if errors.As(err, &groupViolation) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err), "violations": groupViolation.Violations})
+		return
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"msg": "Anime merged successfully!", "anime": anime, "merge": merge, "skipped": skipped})
+}
+
+// GetMerges 获取动漫合并记录，可以用 target_id 筛选
+func (api *Handler) GetMerges(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+	targetID, _ := strconv.Atoi(c.DefaultQuery("target_id", "0"))
+
+	merges, total, err := api.AnimeSrv.GetMerges(uint(targetID), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"merges": merges, "total": total, "page": page, "pageSize": pageSize})
 }
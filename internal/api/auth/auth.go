@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/i18n"
+	"kong-anime-go/internal/middleware"
+	"kong-anime-go/internal/services/auth"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Handler 处理登录和用户管理相关的HTTP请求
+type Handler struct {
+	service *auth.Service
+}
+
+// NewHandler 创建一个新的 AuthHandler
+func NewHandler(service *auth.Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Login 使用登录名和密码登录，返回访问令牌和刷新令牌
+func (h *Handler) Login(c *gin.Context) {
+	var req struct {
+		Username string `json:"username" binding:"required"`
+		Password string `json:"password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	tokens, err := h.service.Login(req.Username, req.Password)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Refresh 使用刷新令牌换取新的访问令牌和刷新令牌
+func (h *Handler) Refresh(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	tokens, err := h.service.Refresh(req.RefreshToken)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout 使请求中的刷新令牌失效，未提供时使当前用户的所有刷新令牌失效
+func (h *Handler) Logout(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	// 请求体可以为空
+	_ = c.ShouldBindJSON(&req)
+	if err := h.service.Logout(middleware.CurrentUserID(c), req.RefreshToken); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+// Me 获取当前登录的用户
+func (h *Handler) Me(c *gin.Context) {
+	c.JSON(http.StatusOK, middleware.CurrentUser(c))
+}
+
+// ChangePassword 修改当前用户的密码，所有刷新令牌失效，返回新的令牌
+func (h *Handler) ChangePassword(c *gin.Context) {
+	var req struct {
+		OldPassword string `json:"old_password" binding:"required"`
+		NewPassword string `json:"new_password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	tokens, err := h.service.ChangePassword(middleware.CurrentUserID(c), req.OldPassword, req.NewPassword)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// GetUsers 获取所有用户
+func (h *Handler) GetUsers(c *gin.Context) {
+	users, err := h.service.GetUsers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"users": users, "total": len(users)})
+}
+
+// CreateUser 创建用户，未指定角色时为 viewer
+func (h *Handler) CreateUser(c *gin.Context) {
+	var req struct {
+		Username    string      `json:"username" binding:"required"`
+		Password    string      `json:"password" binding:"required"`
+		DisplayName string      `json:"display_name"`
+		Role        common.Role `json:"role"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	if req.Role == "" {
+		req.Role = common.RoleViewer
+	}
+	user, _, err := h.service.CreateUser(req.Username, req.Password, req.DisplayName, req.Role)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// SetRole 修改用户的角色
+func (h *Handler) SetRole(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid ID")})
+		return
+	}
+	var req struct {
+		Role common.Role `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	user, err := h.service.SetRole(uint(id), req.Role)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// errorStatus 将登录和用户管理相关的错误映射为HTTP状态码
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, auth.ErrInvalidCredentials), errors.Is(err, auth.ErrInvalidToken):
+		return http.StatusUnauthorized
+	case errors.Is(err, auth.ErrWrongPassword), errors.Is(err, auth.ErrPasswordTooShort),
+		errors.Is(err, auth.ErrEmptyUsername), errors.Is(err, auth.ErrInvalidRole):
+		return http.StatusBadRequest
+	case errors.Is(err, auth.ErrUsernameExists), errors.Is(err, auth.ErrLastAdmin):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
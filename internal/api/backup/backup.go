@@ -0,0 +1,76 @@
+package backup
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"kong-anime-go/internal/i18n"
+	"kong-anime-go/internal/services/backup"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 处理备份和恢复相关的HTTP请求
+type Handler struct {
+	service   *backup.Service
+	scheduler *backup.Scheduler
+}
+
+// NewHandler 创建一个新的 BackupHandler
+func NewHandler(service *backup.Service, scheduler *backup.Scheduler) *Handler {
+	return &Handler{service: service, scheduler: scheduler}
+}
+
+// Backup 下载整个数据库的备份
+func (h *Handler) Backup(c *gin.Context) {
+	c.Header("Content-Type", "application/gzip")
+	c.Header("Content-Disposition", `attachment; filename="`+backup.FileName(time.Now())+`"`)
+	if _, err := h.service.Write(c.Writer); err != nil {
+		if !c.Writer.Written() {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+			return
+		}
+		// 响应已经开始写出，只能记录错误
+		log.Printf("backup: failed to write backup: %v", err)
+		c.Error(err)
+	}
+}
+
+// Restore 上传备份并恢复到没有动漫目录和追番数据的数据库，备份中的同名用户合并到已有用户
+func (h *Handler) Restore(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	defer file.Close()
+
+	manifest, err := h.service.Restore(file)
+	switch {
+	case errors.Is(err, backup.ErrNotEmpty):
+		c.JSON(http.StatusConflict, gin.H{"error": i18n.ErrorMessage(c, err)})
+	case errors.Is(err, backup.ErrInvalidArchive), errors.Is(err, backup.ErrUnsupportedVersion):
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+	default:
+		c.JSON(http.StatusOK, gin.H{"msg": "Restore completed!", "manifest": manifest})
+	}
+}
+
+// GetLocalBackups 列出备份目录中的备份（包含大小和 sha256）及定时备份状态
+func (h *Handler) GetLocalBackups(c *gin.Context) {
+	backups, err := h.scheduler.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"backups": backups, "schedule": h.scheduler.Status()})
+}
@@ -1,12 +1,15 @@
 package category
 
 import (
+	"errors"
 	"kong-anime-go/internal/dao/models"
+	"kong-anime-go/internal/i18n"
 	"kong-anime-go/internal/services/category"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // Handler 处理 Category 相关的服务
@@ -25,12 +28,12 @@ func NewHandler(categoryService *category.Service) *Handler {
 func (h *Handler) Create(c *gin.Context) {
 	var newCategory models.Category
 	if err := c.ShouldBindJSON(&newCategory); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	category, err := h.categoryService.Create(&newCategory)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	c.JSON(http.StatusOK, category)
@@ -41,7 +44,7 @@ func (h *Handler) Delete(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
 	deletedID, err := h.categoryService.Delete(uint(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"msg": "Category deleted successfully!", "id": deletedID})
@@ -52,13 +55,29 @@ func (h *Handler) Update(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
 	var updatedCategory models.Category
 	if err := c.ShouldBindJSON(&updatedCategory); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	updatedCategory.ID = uint(id)
+	var nameExists *category.ErrNameExists
 	category, err := h.categoryService.Update(&updatedCategory)
+	if errors.As(err, &nameExists) {
+		merge, _ := strconv.ParseBool(c.DefaultQuery("merge", "false"))
+		if !merge {
+			c.JSON(http.StatusConflict, gin.H{"error": i18n.ErrorMessage(c, err), "existing": nameExists.Existing})
+			return
+		}
+		// 重命名为已存在的名称时合并到已存在的分类
+		result, err := h.categoryService.Merge(uint(id), nameExists.Existing.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"msg": "Category merged successfully!", "category": result.Target, "impact": result.Impact})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	c.JSON(http.StatusOK, category)
@@ -69,39 +88,131 @@ func (h *Handler) GetByID(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
 	category, err := h.categoryService.GetByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	c.JSON(http.StatusOK, category)
 }
 
-// GetAll 获取所有分类
+// GetAll 获取所有分类的树形结构，flat=true 时返回平铺的列表
 func (h *Handler) GetAll(c *gin.Context) {
-	categories, err := h.categoryService.GetAll()
+	flat, _ := strconv.ParseBool(c.DefaultQuery("flat", "false"))
+	if flat {
+		categories, err := h.categoryService.GetAll()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"categories": categories, "total": len(categories)})
+		return
+	}
+	categories, err := h.categoryService.GetTree()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"categories": categories, "total": len(categories)})
+}
+
+// Reorder 按 ids 的顺序排列分类，未列出的分类保持原有顺序排在后面
+func (h *Handler) Reorder(c *gin.Context) {
+	var req struct {
+		IDs []uint `json:"ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	categories, err := h.categoryService.Reorder(req.IDs)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"categories": categories, "total": len(categories)})
 }
 
+// SetParent 移动分类到 parent_id 指定的分类下，parent_id 为 null 时成为顶层分类
+func (h *Handler) SetParent(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req struct {
+		ParentID *uint `json:"parent_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	category, err := h.categoryService.SetParent(uint(id), req.ParentID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, category)
+}
+
 // GetByName 根据名称获取分类
 func (h *Handler) GetByName(c *gin.Context) {
 	name := c.Query("name")
 	categories, err := h.categoryService.GetByName(name)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"categories": categories, "total": len(categories)})
 }
 
-// GetStats 获取分类统计信息
+// GetStats 按显示顺序获取分类统计信息，附带颜色、图标等显示设置
 func (h *Handler) GetStats(c *gin.Context) {
 	stats, err := h.categoryService.GetStats()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"category_stats": stats})
 }
+
+// PreviewMerge 预览将 source_id 指定的分类合并到当前分类时受影响的项目数量
+func (h *Handler) PreviewMerge(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	sourceID, err := strconv.Atoi(c.Query("source_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid source_id")})
+		return
+	}
+	preview, err := h.categoryService.PreviewMerge(uint(sourceID), uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, preview)
+}
+
+// Merge 将 source_id 指定的分类合并到当前分类，源分类合并后被删除
+func (h *Handler) Merge(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req struct {
+		SourceID uint `json:"source_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	result, err := h.categoryService.Merge(req.SourceID, uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"msg": "Category merged successfully!", "category": result.Target, "impact": result.Impact})
+}
+
+// errorStatus 将合并、层级、显示设置相关的错误映射为HTTP状态码
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, category.ErrMergeIntoSelf), errors.Is(err, category.ErrParentCycle), errors.Is(err, category.ErrTooDeep),
+		errors.Is(err, category.ErrInvalidColor), errors.Is(err, category.ErrInvalidOrder):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
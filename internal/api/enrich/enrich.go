@@ -0,0 +1,79 @@
+package enrich
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/i18n"
+	"kong-anime-go/internal/metadata"
+	animesrv "kong-anime-go/internal/services/anime"
+	"kong-anime-go/internal/services/enrich"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Handler 处理元数据补全相关的HTTP请求
+type Handler struct {
+	service *enrich.Service
+}
+
+// NewHandler 创建一个新的 EnrichHandler
+func NewHandler(service *enrich.Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Enrich 获取动漫的候选元数据并返回逐字段差异，apply 中的字段会被写入
+func (h *Handler) Enrich(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid ID")})
+		return
+	}
+	var req struct {
+		Source     common.ExternalSource `json:"source"`
+		ExternalID string                `json:"external_id"`
+		Apply      []string              `json:"apply"`
+	}
+	// 请求体可以为空，此时只预览
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+			return
+		}
+	}
+	if req.Source != "" && !req.Source.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid source")})
+		return
+	}
+	if err := animesrv.ValidateFields(req.Apply); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+
+	result, err := h.service.Enrich(c.Request.Context(), uint(id), enrich.Request{
+		Source:     req.Source,
+		ExternalID: req.ExternalID,
+		Apply:      req.Apply,
+	})
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.Message(c, "Anime not found")})
+	case errors.Is(err, enrich.ErrNoMatch):
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.ErrorMessage(c, err), "candidates": result.Candidates})
+	case errors.Is(err, metadata.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.ErrorMessage(c, err)})
+	case errors.Is(err, enrich.ErrUnknownSource):
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+	case errors.Is(err, animesrv.ErrExternalIDTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": i18n.ErrorMessage(c, err)})
+	case errors.Is(err, enrich.ErrProvider):
+		c.JSON(http.StatusBadGateway, gin.H{"error": i18n.ErrorMessage(c, err)})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+	default:
+		c.JSON(http.StatusOK, result)
+	}
+}
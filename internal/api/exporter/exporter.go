@@ -0,0 +1,43 @@
+package exporter
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"kong-anime-go/internal/i18n"
+	"kong-anime-go/internal/middleware"
+	"kong-anime-go/internal/services/exporter"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 处理导出相关的HTTP请求
+type Handler struct {
+	service *exporter.Service
+}
+
+// NewHandler 创建一个新的 ExportHandler
+func NewHandler(service *exporter.Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ExportFollows 以 mal、csv 或 json 格式流式导出当前用户的所有追番
+func (h *Handler) ExportFollows(c *gin.Context) {
+	format := c.DefaultQuery("format", exporter.FormatJSON)
+	contentType, ext, err := exporter.ContentType(format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+
+	fileName := "follows-" + time.Now().Format("20060102") + "." + ext
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", `attachment; filename="`+fileName+`"`)
+	c.Status(http.StatusOK)
+	if err := h.service.ExportFollows(c.Writer, middleware.CurrentUserID(c), format); err != nil {
+		// 响应已经开始写出，只能记录错误
+		log.Printf("export: failed to export follows as %s: %v", format, err)
+		c.Error(err)
+	}
+}
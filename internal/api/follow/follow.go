@@ -1,15 +1,18 @@
 package follow
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
-	"time"
 
 	"kong-anime-go/internal/common"
 	"kong-anime-go/internal/dao/models"
+	"kong-anime-go/internal/i18n"
+	"kong-anime-go/internal/middleware"
 	"kong-anime-go/internal/services/follow"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // Handler 处理追番相关的HTTP请求
@@ -22,95 +25,109 @@ func NewHandler(service *follow.Service) *Handler {
 	return &Handler{service: service}
 }
 
-// Create 创建追番
+// validScore 检查评分是否在 0-10 之间，未评分时为 nil
+func validScore(score *float64) bool {
+	return score == nil || (*score >= 0 && *score <= 10)
+}
+
+// Create 为当前用户创建追番
 func (h *Handler) Create(c *gin.Context) {
 	var follow models.Follow
 	if err := c.ShouldBindJSON(&follow); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
-	if !follow.Category.IsValid() {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category"})
+	if err := h.service.ValidateCategory(follow.Category); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	if !follow.Status.IsValid() {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid status")})
+		return
+	}
+	if !validScore(follow.Score) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid score")})
 		return
 	}
-	existingFollow, err := h.service.GetByAnimeID(follow.AnimeID)
+	existingFollow, err := h.service.GetByAnimeID(middleware.CurrentUserID(c), follow.AnimeID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	if existingFollow != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Follow already exists for this anime"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Follow already exists for this anime")})
 		return
 	}
 	follow.FinishedAt = nil // 设置为空值
+	follow.DroppedAt = nil
 
-	createdFollow, err := h.service.Create(&follow)
+	createdFollow, err := h.service.Create(middleware.CurrentUserID(c), &follow)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	c.JSON(http.StatusOK, createdFollow)
 }
 
-// Delete 删除追番
+// Delete 删除当前用户的追番
 func (h *Handler) Delete(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid ID")})
 		return
 	}
-	if _, err := h.service.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if _, err := h.service.Delete(middleware.CurrentUserID(c), uint(id)); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Deleted successfully", "id": id})
 }
 
-// Update 更新追番
+// Update 更新当前用户的追番，不允许的状态变更返回 409
 func (h *Handler) Update(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
 	var updatedFollow models.Follow
 	if err := c.ShouldBindJSON(&updatedFollow); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
-	if !updatedFollow.Category.IsValid() {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category"})
+	if err := h.service.ValidateCategory(updatedFollow.Category); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	if !updatedFollow.Status.IsValid() {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid status")})
+		return
+	}
+	if !validScore(updatedFollow.Score) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid score")})
 		return
 	}
 	updatedFollow.ID = uint(id)
-	follow, err := h.service.Update(&updatedFollow)
+	follow, err := h.service.Update(middleware.CurrentUserID(c), &updatedFollow)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	c.JSON(http.StatusOK, follow)
 }
 
-// GetByID 根据ID获取追番
+// GetByID 根据ID获取当前用户的追番，其他用户的追番返回 404
 func (h *Handler) GetByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid ID")})
 		return
 	}
-	follow, err := h.service.GetByID(uint(id))
+	follow, err := h.service.GetByID(middleware.CurrentUserID(c), uint(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	c.JSON(http.StatusOK, follow)
 }
 
-// GetAll 获取所有追番
+// GetAll 获取当前用户的所有追番
 func (h *Handler) GetAll(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
@@ -121,7 +138,7 @@ func (h *Handler) GetAll(c *gin.Context) {
 
 	// 验证 sorter 参数，只允许 "asc" 或 "desc"
 	if sorter != "" && sorter != "asc" && sorter != "desc" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sorter"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid sorter")})
 		return
 	}
 
@@ -146,68 +163,173 @@ func (h *Handler) GetAll(c *gin.Context) {
 		}
 	}
 
-	follows, total, err := h.service.GetAll(page, pageSize, category, status, &name, sortField)
+	follows, total, err := h.service.GetAll(middleware.CurrentUserID(c), page, pageSize, category, status, &name, sortField)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": follows, "total": total})
 }
 
-// UpdateStatus 更新追番状态
+// UpdateStatus 按状态机更新追番状态，不允许的变更返回 409
 func (h *Handler) UpdateStatus(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid ID")})
 		return
 	}
 	var request struct {
 		Status common.FollowStatus `json:"status"`
 	}
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	if !request.Status.IsValid() {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid status")})
 		return
 	}
-	follow, err := h.service.GetByID(uint(id))
+	updatedFollow, err := h.service.UpdateStatus(middleware.CurrentUserID(c), uint(id), request.Status)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
-	follow.Status = request.Status
-	if request.Status == common.FollowStatusWatched {
-		now := time.Now()
-		follow.FinishedAt = &now
-	} else {
-		follow.FinishedAt = nil // 设置为空值
+	c.JSON(http.StatusOK, updatedFollow)
+}
+
+// categoryResponse 追番分类的响应，string 为分类名称，内置分类的名称和描述按请求的语言翻译
+type categoryResponse struct {
+	Value       common.FollowCategory `json:"value"`
+	String      string                `json:"string"`
+	Description string                `json:"description"`
+	Color       string                `json:"color"`
+	SortOrder   int                   `json:"sort_order"`
+}
+
+func toCategoryResponse(c *gin.Context, category models.FollowCategory) categoryResponse {
+	lang := i18n.FromContext(c)
+	return categoryResponse{
+		Value:       category.Value,
+		String:      i18n.FollowCategoryText(lang, category.Name),
+		Description: i18n.FollowCategoryText(lang, category.Description),
+		Color:       category.Color,
+		SortOrder:   category.SortOrder,
 	}
-	updatedFollow, err := h.service.Update(follow)
+}
+
+// categoryRequest 创建或更新追番分类的请求
+type categoryRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	Color       string `json:"color"`
+}
+
+// GetAllCategories 按显示顺序获取所有追番分类
+func (h *Handler) GetAllCategories(c *gin.Context) {
+	categories, err := h.service.GetCategories()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
-	c.JSON(http.StatusOK, updatedFollow)
+	resp := make([]categoryResponse, 0, len(categories))
+	for _, category := range categories {
+		resp = append(resp, toCategoryResponse(c, category))
+	}
+	c.JSON(http.StatusOK, gin.H{"categories": resp})
 }
 
-// GetAllCategories 获取所有追番分类
-func (h *Handler) GetAllCategories(c *gin.Context) {
-	type CategoryResponse struct {
-		Value       int    `json:"value"`
-		String      string `json:"string"`
-		Description string `json:"description"`
+// CreateCategory 创建追番分类，分类值自动分配
+func (h *Handler) CreateCategory(c *gin.Context) {
+	var req categoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	category, err := h.service.CreateCategory(&models.FollowCategory{
+		Name:        req.Name,
+		Description: req.Description,
+		Color:       req.Color,
+	})
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, toCategoryResponse(c, *category))
+}
+
+// UpdateCategory 更新追番分类的名称、描述和颜色
+func (h *Handler) UpdateCategory(c *gin.Context) {
+	value, err := strconv.Atoi(c.Param("value"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid category")})
+		return
 	}
+	var req categoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	category, err := h.service.UpdateCategory(&models.FollowCategory{
+		Value:       common.FollowCategory(value),
+		Name:        req.Name,
+		Description: req.Description,
+		Color:       req.Color,
+	})
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, toCategoryResponse(c, *category))
+}
 
-	var categories []CategoryResponse
-	for _, category := range common.AllFollowCategories() {
-		categories = append(categories, CategoryResponse{
-			Value:       int(category),
-			String:      category.String(),
-			Description: category.Description(),
-		})
+// DeleteCategory 删除追番分类，仍有追番使用该分类时返回 409
+func (h *Handler) DeleteCategory(c *gin.Context) {
+	value, err := strconv.Atoi(c.Param("value"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid category")})
+		return
 	}
+	deleted, err := h.service.DeleteCategory(common.FollowCategory(value))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted successfully", "value": deleted})
+}
+
+// ReorderCategories 按 values 的顺序排列追番分类，未列出的分类保持原有顺序排在后面
+func (h *Handler) ReorderCategories(c *gin.Context) {
+	var req struct {
+		Values []common.FollowCategory `json:"values" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	categories, err := h.service.ReorderCategories(req.Values)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	resp := make([]categoryResponse, 0, len(categories))
+	for _, category := range categories {
+		resp = append(resp, toCategoryResponse(c, category))
+	}
+	c.JSON(http.StatusOK, gin.H{"categories": resp})
+}
 
-	c.JSON(http.StatusOK, gin.H{"categories": categories})
+// errorStatus 将追番分类和追番状态相关的错误映射为HTTP状态码
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, follow.ErrUnknownCategory), errors.Is(err, follow.ErrEmptyCategoryName),
+		errors.Is(err, follow.ErrInvalidColor), errors.Is(err, follow.ErrInvalidOrder):
+		return http.StatusBadRequest
+	case errors.Is(err, follow.ErrCategoryNameExists), errors.Is(err, follow.ErrCategoryInUse),
+		errors.Is(err, follow.ErrCategoryUsedByRule), errors.Is(err, follow.ErrInvalidTransition):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
 }
@@ -0,0 +1,121 @@
+package importer
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/i18n"
+	"kong-anime-go/internal/middleware"
+	"kong-anime-go/internal/services/importer"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 处理导入相关的HTTP请求
+type Handler struct {
+	service *importer.Service
+}
+
+// NewHandler 创建一个新的 ImportHandler
+func NewHandler(service *importer.Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ImportMAL 上传 MyAnimeList 导出的 animelist.xml 并创建导入任务，追番导入到当前用户
+// 只有 editor 及以上角色可以为匹配不到的记录新建动漫
+func (h *Handler) ImportMAL(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	categoryVal, err := strconv.Atoi(c.DefaultQuery("category", strconv.Itoa(int(common.FollowCategoryClassic))))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid category")})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	defer file.Close()
+
+	user := middleware.CurrentUser(c)
+	job, err := h.service.ImportMAL(user.ID, fileHeader.Filename, file, common.FollowCategory(categoryVal), dryRun, user.Role.Allows(common.RoleEditor))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"msg": "Import job created!", "job": job})
+}
+
+// ImportCatalogue 上传动漫目录 CSV/TSV 并导入，dry_run=true 时只校验不写入，force=true 时跳过重复检查
+func (h *Handler) ImportCatalogue(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	force, _ := strconv.ParseBool(c.DefaultQuery("force", "false"))
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	defer file.Close()
+
+	delimiter := ','
+	if c.Query("format") == "tsv" || strings.HasSuffix(strings.ToLower(fileHeader.Filename), ".tsv") {
+		delimiter = '\t'
+	}
+
+	job, err := h.service.ImportCatalogue(middleware.CurrentUserID(c), fileHeader.Filename, file, delimiter, dryRun, force)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	if job.Status == importer.JobStatusFailed {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": i18n.Message(c, job.Error), "job": job})
+		return
+	}
+	msg := "Catalogue imported!"
+	if dryRun {
+		msg = "Catalogue validated!"
+	}
+	c.JSON(http.StatusOK, gin.H{"msg": msg, "job": job})
+}
+
+// GetJobs 获取导入任务
+func (h *Handler) GetJobs(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+
+	jobs, total, err := h.service.GetJobs(middleware.CurrentUserID(c), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": jobs, "total": total})
+}
+
+// GetJob 根据ID获取导入任务及每一行的处理结果
+func (h *Handler) GetJob(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid ID")})
+		return
+	}
+	job, err := h.service.GetJob(middleware.CurrentUserID(c), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.Message(c, "Import job not found")})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
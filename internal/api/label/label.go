@@ -0,0 +1,48 @@
+package label
+
+import (
+	"net/http"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 处理枚举显示名称相关的HTTP请求
+type Handler struct{}
+
+// NewHandler 创建一个新的 LabelHandler
+func NewHandler() *Handler {
+	return &Handler{}
+}
+
+// Label 一个枚举值及其显示名称，value 为接口中使用的值，string 为其字符串表示
+type Label struct {
+	Value  any    `json:"value"`
+	String string `json:"string"`
+	Label  string `json:"label"`
+}
+
+func labelsOf[T interface{ ~int | ~string }](lang i18n.Lang, kind string, values []T, toString func(T) string) []Label {
+	labels := make([]Label, len(values))
+	for i, value := range values {
+		s := toString(value)
+		labels[i] = Label{Value: value, String: s, Label: i18n.Label(lang, kind, s)}
+	}
+	return labels
+}
+
+// GetAll 按请求的语言获取所有枚举的显示名称
+func (h *Handler) GetAll(c *gin.Context) {
+	lang := i18n.FromContext(c)
+	c.JSON(http.StatusOK, gin.H{
+		"lang":              lang,
+		"media_types":       labelsOf(lang, "media_type", common.AllMediaTypes(), common.MediaType.String),
+		"airing_statuses":   labelsOf(lang, "airing_status", common.AllAiringStatuses(), common.AiringStatus.String),
+		"follow_statuses":   labelsOf(lang, "follow_status", common.AllFollowStatuses(), common.FollowStatus.String),
+		"credit_roles":      labelsOf(lang, "credit_role", common.AllCreditRoles(), common.CreditRole.String),
+		"tag_cardinalities": labelsOf(lang, "tag_cardinality", common.AllTagCardinalities(), func(tc common.TagCardinality) string { return string(tc) }),
+		"roles":             labelsOf(lang, "role", common.AllRoles(), func(r common.Role) string { return string(r) }),
+	})
+}
@@ -0,0 +1,206 @@
+package person
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao/models"
+	"kong-anime-go/internal/i18n"
+	"kong-anime-go/internal/middleware"
+	"kong-anime-go/internal/services/person"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 处理职员和声优相关的HTTP请求
+type Handler struct {
+	service *person.Service
+}
+
+// NewHandler 创建一个新的 PersonHandler
+func NewHandler(service *person.Service) *Handler {
+	return &Handler{service: service}
+}
+
+func bindPerson(c *gin.Context, person *models.Person) error {
+	var req struct {
+		Name    string   `json:"name"`
+		Aliases []string `json:"aliases"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return err
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		return errors.New("Name is required")
+	}
+	person.Name = strings.TrimSpace(req.Name)
+	person.Aliases = strings.Join(req.Aliases, ",")
+	return nil
+}
+
+// parseRole 解析查询参数中的担当，未传入时返回 nil
+func parseRole(c *gin.Context) (*common.CreditRole, error) {
+	roleStr := c.Query("role")
+	if roleStr == "" {
+		return nil, nil
+	}
+	roleVal, err := strconv.Atoi(roleStr)
+	role := common.CreditRole(roleVal)
+	if err != nil || !role.IsValid() {
+		return nil, errors.New("Invalid role")
+	}
+	return &role, nil
+}
+
+// Create 创建人物
+func (h *Handler) Create(c *gin.Context) {
+	var newPerson models.Person
+	if err := bindPerson(c, &newPerson); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	person, err := h.service.Create(&newPerson)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, person)
+}
+
+// Delete 删除人物
+func (h *Handler) Delete(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	deletedID, err := h.service.Delete(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"msg": "Person deleted successfully!", "id": deletedID})
+}
+
+// Update 更新人物
+func (h *Handler) Update(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var updatedPerson models.Person
+	if err := bindPerson(c, &updatedPerson); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	updatedPerson.ID = uint(id)
+	person, err := h.service.Update(&updatedPerson)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, person)
+}
+
+// GetByID 根据ID获取人物
+func (h *Handler) GetByID(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	person, err := h.service.GetByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.Message(c, "Person not found")})
+		return
+	}
+	c.JSON(http.StatusOK, person)
+}
+
+// GetAll 获取人物列表
+func (h *Handler) GetAll(c *gin.Context) {
+	name := c.Query("name")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+
+	persons, total, err := h.service.GetAll(name, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"people": persons, "total": total, "page": page, "pageSize": pageSize})
+}
+
+// GetFilmography 获取人物参与的作品
+func (h *Handler) GetFilmography(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	role, err := parseRole(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	credits, err := h.service.GetFilmography(uint(id), role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"credits": credits, "total": len(credits)})
+}
+
+// GetWatchedStats 获取在当前用户看过的追番中出现最多的人物
+func (h *Handler) GetWatchedStats(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	role, err := parseRole(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	stats, err := h.service.GetWatchedStats(middleware.CurrentUserID(c), role, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"person_stats": stats})
+}
+
+// CreateCredit 添加职员或声优的担当
+func (h *Handler) CreateCredit(c *gin.Context) {
+	var req struct {
+		PersonID  uint              `json:"person_id"`
+		AnimeID   *uint             `json:"anime_id"`
+		MovieID   *uint             `json:"movie_id"`
+		Role      common.CreditRole `json:"role"`
+		Character string            `json:"character"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	credit := &models.Credit{
+		PersonID:  req.PersonID,
+		AnimeID:   req.AnimeID,
+		MovieID:   req.MovieID,
+		Role:      req.Role,
+		Character: strings.TrimSpace(req.Character),
+	}
+	credit, err := h.service.CreateCredit(credit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, credit)
+}
+
+// DeleteCredit 删除担当
+func (h *Handler) DeleteCredit(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	deletedID, err := h.service.DeleteCredit(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"msg": "Credit deleted successfully!", "id": deletedID})
+}
+
+// GetAnimeCredits 获取动漫的职员和声优
+func (h *Handler) GetAnimeCredits(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	credits, err := h.service.GetAnimeCredits(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"credits": credits, "total": len(credits)})
+}
@@ -1,6 +1,7 @@
 package ping
 
 import (
+	"kong-anime-go/internal/i18n"
 	pingsrv "kong-anime-go/internal/services/ping"
 	"net/http"
 
@@ -29,7 +30,17 @@ func (api *Handler) GetHello(c *gin.Context) {
 func (api *Handler) GetPing(c *gin.Context) {
 	p, err := api.PingService.GetPing()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
 	}
 	c.JSON(http.StatusOK, p)
 }
+
+// GetHealth 返回健康检查结果，不健康时返回 503
+func (api *Handler) GetHealth(c *gin.Context) {
+	health := api.PingService.GetHealth()
+	if health.Status != pingsrv.HealthOK {
+		c.JSON(http.StatusServiceUnavailable, health)
+		return
+	}
+	c.JSON(http.StatusOK, health)
+}
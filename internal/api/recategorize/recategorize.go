@@ -0,0 +1,82 @@
+package recategorize
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"kong-anime-go/internal/i18n"
+	"kong-anime-go/internal/middleware"
+	"kong-anime-go/internal/services/follow"
+	"kong-anime-go/internal/services/recategorize"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 处理追番自动归类相关的HTTP请求
+type Handler struct {
+	service *recategorize.Service
+}
+
+// NewHandler 创建一个新的 RecategorizeHandler
+func NewHandler(service *recategorize.Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Preview 预览自动归类对当前用户的追番将要发生的变更
+func (h *Handler) Preview(c *gin.Context) {
+	userID := middleware.CurrentUserID(c)
+	changes, err := h.service.Preview(&userID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"rules": h.service.Rules(), "changes": changes, "total": len(changes)})
+}
+
+// Run 立即对当前用户的追番执行一次自动归类
+func (h *Handler) Run(c *gin.Context) {
+	userID := middleware.CurrentUserID(c)
+	run, err := h.service.Run(recategorize.TriggerManual, &userID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err), "run": run})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"msg": "Recategorize finished!", "run": run})
+}
+
+// GetRuns 获取当前用户手动执行和定时执行的自动归类记录
+func (h *Handler) GetRuns(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+
+	runs, total, err := h.service.GetRuns(middleware.CurrentUserID(c), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": runs, "total": total})
+}
+
+// GetRunByID 根据ID获取执行记录，只包含当前用户追番的变更明细
+func (h *Handler) GetRunByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid ID")})
+		return
+	}
+	run, err := h.service.GetRunByID(middleware.CurrentUserID(c), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.Message(c, "Run not found")})
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
+
+// errorStatus 将服务层错误映射为HTTP状态码，规则使用的追番分类已不存在时返回 409
+func errorStatus(err error) int {
+	if errors.Is(err, follow.ErrUnknownCategory) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,218 @@
+package studio
+
+import (
+	"errors"
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao/models"
+	"kong-anime-go/internal/i18n"
+	"kong-anime-go/internal/middleware"
+	"kong-anime-go/internal/services/studio"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Handler 处理 Studio 相关的服务
+type Handler struct {
+	studioService *studio.Service
+}
+
+// NewHandler 创建一个新的 StudioHandler
+func NewHandler(studioService *studio.Service) *Handler {
+	return &Handler{
+		studioService: studioService,
+	}
+}
+
+func bindStudio(c *gin.Context, studio *models.Studio) error {
+	var req struct {
+		Name    string   `json:"name"`
+		Aliases []string `json:"aliases"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return err
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		return errors.New("Name is required")
+	}
+	studio.Name = strings.TrimSpace(req.Name)
+	studio.Aliases = strings.Join(req.Aliases, ",")
+	return nil
+}
+
+// Create 创建一个新的制作公司
+func (h *Handler) Create(c *gin.Context) {
+	var newStudio models.Studio
+	if err := bindStudio(c, &newStudio); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	var nameExists *studio.ErrNameExists
+	studio, err := h.studioService.Create(&newStudio)
+	if errors.As(err, &nameExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": i18n.ErrorMessage(c, err), "existing": nameExists.Existing})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, studio)
+}
+
+// Delete 删除一个制作公司，有关联的动漫时返回 409，需要先合并到其他制作公司
+func (h *Handler) Delete(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	deletedID, err := h.studioService.Delete(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"msg": "Studio deleted successfully!", "id": deletedID})
+}
+
+// Update 更新一个制作公司，名称或别名已经对应其他制作公司时返回 409，merge=true 时改为合并到该制作公司（需要 admin）
+func (h *Handler) Update(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var updatedStudio models.Studio
+	if err := bindStudio(c, &updatedStudio); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	updatedStudio.ID = uint(id)
+	var nameExists *studio.ErrNameExists
+	studio, err := h.studioService.Update(&updatedStudio)
+	if errors.As(err, &nameExists) {
+		merge, _ := strconv.ParseBool(c.DefaultQuery("merge", "false"))
+		if !merge {
+			c.JSON(http.StatusConflict, gin.H{"error": i18n.ErrorMessage(c, err), "existing": nameExists.Existing})
+			return
+		}
+		// 合并与 POST /studios/:id/merge 一样需要 admin
+		if !middleware.CurrentUser(c).Role.Allows(common.RoleAdmin) {
+			c.JSON(http.StatusForbidden, gin.H{"error": i18n.Message(c, "Insufficient permissions"), "required_role": common.RoleAdmin})
+			return
+		}
+		result, err := h.studioService.Merge(uint(id), nameExists.Existing.ID)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"msg": "Studio merged successfully!", "studio": result.Target, "impact": result.Impact})
+		return
+	}
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, studio)
+}
+
+// GetByID 根据ID获取制作公司
+func (h *Handler) GetByID(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	studio, err := h.studioService.GetByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.Message(c, "Studio not found")})
+		return
+	}
+	c.JSON(http.StatusOK, studio)
+}
+
+// GetAll 获取所有制作公司
+func (h *Handler) GetAll(c *gin.Context) {
+	studios, err := h.studioService.GetAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"studios": studios, "total": len(studios)})
+}
+
+// GetByName 根据名称或别名获取制作公司
+func (h *Handler) GetByName(c *gin.Context) {
+	name := c.Query("name")
+	studios, err := h.studioService.GetByName(name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"studios": studios, "total": len(studios)})
+}
+
+// GetAnimes 获取制作公司参与制作的动漫
+func (h *Handler) GetAnimes(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
+
+	animes, total, err := h.studioService.GetAnimes(uint(id), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"animes": animes, "total": total, "page": page, "pageSize": pageSize})
+}
+
+// GetStats 获取制作公司统计信息
+func (h *Handler) GetStats(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	stats, err := h.studioService.GetStats(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"studio_stats": stats})
+}
+
+// PreviewMerge 预览将 source_id 指定的制作公司合并到当前制作公司时受影响的动漫数量
+func (h *Handler) PreviewMerge(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	sourceID, err := strconv.Atoi(c.Query("source_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid source_id")})
+		return
+	}
+	preview, err := h.studioService.PreviewMerge(uint(sourceID), uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, preview)
+}
+
+// Merge 将 source_id 指定的制作公司合并到当前制作公司，源制作公司的名称和别名成为当前制作公司的别名，合并后被删除
+func (h *Handler) Merge(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req struct {
+		SourceID uint `json:"source_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	result, err := h.studioService.Merge(req.SourceID, uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"msg": "Studio merged successfully!", "studio": result.Target, "impact": result.Impact})
+}
+
+// errorStatus 将合并、删除和名称冲突相关的错误映射为HTTP状态码
+func errorStatus(err error) int {
+	var nameExists *studio.ErrNameExists
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, studio.ErrMergeIntoSelf):
+		return http.StatusBadRequest
+	case errors.Is(err, studio.ErrHasAnimes), errors.As(err, &nameExists):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
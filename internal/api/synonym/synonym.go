@@ -0,0 +1,98 @@
+package synonym
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/i18n"
+	"kong-anime-go/internal/services/synonym"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Handler 处理同义词相关的HTTP请求
+type Handler struct {
+	synonymService *synonym.Service
+}
+
+// NewHandler 创建一个新的 SynonymHandler
+func NewHandler(synonymService *synonym.Service) *Handler {
+	return &Handler{
+		synonymService: synonymService,
+	}
+}
+
+// Create 登记一个同义词
+func (h *Handler) Create(c *gin.Context) {
+	var req struct {
+		Kind     common.SynonymKind `json:"kind" binding:"required"`
+		Name     string             `json:"name" binding:"required"`
+		TargetID uint               `json:"target_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	created, err := h.synonymService.Create(req.Kind, req.Name, req.TargetID)
+	var conflict *synonym.ErrConflict
+	switch {
+	case errors.As(err, &conflict):
+		c.JSON(http.StatusConflict, gin.H{"error": i18n.ErrorMessage(c, err), "target_id": conflict.TargetID, "target_name": conflict.TargetName})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.ErrorMessage(c, err)})
+	case errors.Is(err, synonym.ErrInvalidKind), errors.Is(err, synonym.ErrEmptyName), errors.Is(err, synonym.ErrRedundant):
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+	default:
+		c.JSON(http.StatusOK, created)
+	}
+}
+
+// Delete 删除一个同义词
+func (h *Handler) Delete(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	deletedID, err := h.synonymService.Delete(uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.Message(c, "Synonym not found")})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"msg": "Synonym deleted successfully!", "id": deletedID})
+}
+
+// GetAll 获取同义词，可按 kind 和 target_id 过滤
+func (h *Handler) GetAll(c *gin.Context) {
+	targetID, _ := strconv.Atoi(c.DefaultQuery("target_id", "0"))
+	synonyms, err := h.synonymService.GetAll(common.SynonymKind(c.Query("kind")), uint(targetID))
+	if errors.Is(err, synonym.ErrInvalidKind) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"synonyms": synonyms, "total": len(synonyms)})
+}
+
+// Resolve 查看名称会被解析到哪个标签或分类
+func (h *Handler) Resolve(c *gin.Context) {
+	resolution, err := h.synonymService.Resolve(common.SynonymKind(c.Query("kind")), c.Query("name"))
+	switch {
+	case errors.Is(err, synonym.ErrInvalidKind):
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.Message(c, "Name does not resolve to an existing entity")})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+	default:
+		c.JSON(http.StatusOK, resolution)
+	}
+}
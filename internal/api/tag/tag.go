@@ -1,12 +1,15 @@
 package tag
 
 import (
+	"errors"
 	"kong-anime-go/internal/dao/models"
+	"kong-anime-go/internal/i18n"
 	"kong-anime-go/internal/services/tag"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // Handler 处理 Tag 相关的服务
@@ -25,12 +28,12 @@ func NewHandler(tagService *tag.Service) *Handler {
 func (h *Handler) Create(c *gin.Context) {
 	var newTag models.Tag
 	if err := c.ShouldBindJSON(&newTag); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	tag, err := h.tagService.Create(&newTag)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	c.JSON(http.StatusOK, tag)
@@ -41,7 +44,7 @@ func (h *Handler) Delete(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
 	deletedID, err := h.tagService.Delete(uint(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"msg": "Tag deleted successfully!", "id": deletedID})
@@ -52,13 +55,29 @@ func (h *Handler) Update(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
 	var updatedTag models.Tag
 	if err := c.ShouldBindJSON(&updatedTag); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	updatedTag.ID = uint(id)
+	var nameExists *tag.ErrNameExists
 	tag, err := h.tagService.Update(&updatedTag)
+	if errors.As(err, &nameExists) {
+		merge, _ := strconv.ParseBool(c.DefaultQuery("merge", "false"))
+		if !merge {
+			c.JSON(http.StatusConflict, gin.H{"error": i18n.ErrorMessage(c, err), "existing": nameExists.Existing})
+			return
+		}
+		// 重命名为已存在的名称时合并到已存在的标签
+		result, err := h.tagService.Merge(uint(id), nameExists.Existing.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"msg": "Tag merged successfully!", "tag": result.Target, "impact": result.Impact})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	c.JSON(http.StatusOK, tag)
@@ -69,39 +88,204 @@ func (h *Handler) GetByID(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
 	tag, err := h.tagService.GetByID(uint(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	c.JSON(http.StatusOK, tag)
 }
 
-// GetAll 获取所有标签
+// GetAll 获取所有标签的树形结构，flat=true 时返回平铺的列表
 func (h *Handler) GetAll(c *gin.Context) {
-	tags, err := h.tagService.GetAll()
+	flat, _ := strconv.ParseBool(c.DefaultQuery("flat", "false"))
+	if flat {
+		tags, err := h.tagService.GetAll()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"tags": tags, "total": len(tags)})
+		return
+	}
+	tags, err := h.tagService.GetTree()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"tags": tags, "total": len(tags)})
+}
+
+// Reorder 按 ids 的顺序排列标签，未列出的标签保持原有顺序排在后面
+func (h *Handler) Reorder(c *gin.Context) {
+	var req struct {
+		IDs []uint `json:"ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	tags, err := h.tagService.Reorder(req.IDs)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"tags": tags, "total": len(tags)})
 }
 
+// SetParent 移动标签到 parent_id 指定的标签下，parent_id 为 null 时成为顶层标签
+func (h *Handler) SetParent(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req struct {
+		ParentID *uint `json:"parent_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	tag, err := h.tagService.SetParent(uint(id), req.ParentID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, tag)
+}
+
 // GetByName 根据名称获取标签
 func (h *Handler) GetByName(c *gin.Context) {
 	name := c.Query("name")
 	tags, err := h.tagService.GetByName(name)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"tags": tags, "total": len(tags)})
 }
 
-// GetStats 获取标签统计信息
+// GetStats 按显示顺序获取标签统计信息，附带颜色、图标等显示设置
 func (h *Handler) GetStats(c *gin.Context) {
 	stats, err := h.tagService.GetStats()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"tag_stats": stats})
 }
+
+// PreviewMerge 预览将 source_id 指定的标签合并到当前标签时受影响的项目数量
+func (h *Handler) PreviewMerge(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	sourceID, err := strconv.Atoi(c.Query("source_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.Message(c, "Invalid source_id")})
+		return
+	}
+	preview, err := h.tagService.PreviewMerge(uint(sourceID), uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, preview)
+}
+
+// Merge 将 source_id 指定的标签合并到当前标签，源标签合并后被删除
+func (h *Handler) Merge(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req struct {
+		SourceID uint `json:"source_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	result, err := h.tagService.Merge(req.SourceID, uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"msg": "Tag merged successfully!", "tag": result.Target, "impact": result.Impact})
+}
+
+// errorStatus 将合并、层级、显示设置和标签组相关的错误映射为HTTP状态码
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, tag.ErrMergeIntoSelf), errors.Is(err, tag.ErrParentCycle), errors.Is(err, tag.ErrTooDeep),
+		errors.Is(err, tag.ErrInvalidColor), errors.Is(err, tag.ErrInvalidOrder), errors.Is(err, tag.ErrInvalidCardinality):
+		return http.StatusBadRequest
+	case errors.Is(err, tag.ErrGroupNameExists):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GetGroups 获取所有标签组及组内的标签
+func (h *Handler) GetGroups(c *gin.Context) {
+	groups, err := h.tagService.GetGroups()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"groups": groups, "total": len(groups)})
+}
+
+// CreateGroup 创建一个新的标签组
+func (h *Handler) CreateGroup(c *gin.Context) {
+	var group models.TagGroup
+	if err := c.ShouldBindJSON(&group); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	created, err := h.tagService.CreateGroup(&group)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, created)
+}
+
+// UpdateGroup 更新标签组
+func (h *Handler) UpdateGroup(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var group models.TagGroup
+	if err := c.ShouldBindJSON(&group); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	group.ID = uint(id)
+	updated, err := h.tagService.UpdateGroup(&group)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteGroup 删除标签组
+func (h *Handler) DeleteGroup(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	deletedID, err := h.tagService.DeleteGroup(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"msg": "Tag group deleted successfully!", "id": deletedID})
+}
+
+// SetGroup 设置标签所属的标签组，group_id 为 null 时移出标签组
+func (h *Handler) SetGroup(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req struct {
+		GroupID *uint `json:"group_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	tag, err := h.tagService.SetGroup(uint(id), req.GroupID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": i18n.ErrorMessage(c, err)})
+		return
+	}
+	c.JSON(http.StatusOK, tag)
+}
@@ -0,0 +1,14 @@
+package common
+
+// IsHexColor 检查颜色是否为 #RGB 或 #RRGGBB 格式
+func IsHexColor(s string) bool {
+	if len(s) != 4 && len(s) != 7 || s[0] != '#' {
+		return false
+	}
+	for _, c := range s[1:] {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
@@ -1,9 +1,11 @@
 package common
 
+import "strings"
+
 // FollowCategory 追番分类
 type FollowCategory int
 
-// 追番分类
+// 内置的追番分类值，分类的名称、描述等保存在 follow_categories 表中，可以新增其他分类值
 const (
 	FollowCategoryClassic FollowCategory = iota
 	FollowCategoryHighQuality
@@ -13,34 +15,124 @@ const (
 	FollowCategoryUnknown = 999
 )
 
-// String 返回追番分类的字符串表示
-func (fc FollowCategory) String() string {
-	return [...]string{"旧时代的残党", "我们仍未知道那天所看见的番剧的名字", "新番妙妙屋", "厕纸", "神！"}[fc]
+// MediaType 动漫类型
+type MediaType int
+
+// 动漫类型
+const (
+	MediaTypeTV MediaType = iota
+	MediaTypeOVA
+	MediaTypeONA
+	MediaTypeSpecial
+	MediaTypeWeb
+	MediaTypeUnknown = 999
+)
+
+// String 返回动漫类型的字符串表示
+func (mt MediaType) String() string {
+	if !mt.IsValid() {
+		return "unknown"
+	}
+	return [...]string{"tv", "ova", "ona", "special", "web"}[mt]
 }
 
-// Description 返回追番分类的描述
-func (fc FollowCategory) Description() string {
-	return [...]string{"经典老番或长篇番", "听说高质量的番", "看新番导视比较感兴趣的番", "专门用来找乐子杀时间的番", "无需多言"}[fc]
+// EpisodesUnknown 集数未知（如尚未公布总集数的连载）
+const EpisodesUnknown = 0
+
+// MaxEpisodes 返回该类型允许的最大集数，长篇 TV 动画可达数千集，网络动画通常不超过数百集
+func (mt MediaType) MaxEpisodes() int {
+	switch mt {
+	case MediaTypeTV:
+		return 3000
+	case MediaTypeOVA:
+		return 26
+	case MediaTypeONA, MediaTypeWeb:
+		return 1000
+	case MediaTypeSpecial:
+		return 12
+	default:
+		return 0
+	}
 }
 
-// IsValid 检查追番分类是否合法
-func (fc FollowCategory) IsValid() bool {
-	switch fc {
-	case FollowCategoryClassic, FollowCategoryHighQuality, FollowCategoryNew, FollowCategoryToiletPaper, FollowCategoryMasterpiece:
+// ValidEpisodes 检查集数是否符合该类型：未知，或在 1 到该类型的最大集数之间
+func (mt MediaType) ValidEpisodes(episodes int) bool {
+	if episodes == EpisodesUnknown {
+		return true
+	}
+	return episodes >= 1 && episodes <= mt.MaxEpisodes()
+}
+
+// IsValid 检查动漫类型是否合法
+func (mt MediaType) IsValid() bool {
+	switch mt {
+	case MediaTypeTV, MediaTypeOVA, MediaTypeONA, MediaTypeSpecial, MediaTypeWeb:
 		return true
 	default:
 		return false
 	}
 }
 
-// AllFollowCategories 返回所有追番分类
-func AllFollowCategories() []FollowCategory {
-	return []FollowCategory{
-		FollowCategoryClassic,
-		FollowCategoryHighQuality,
-		FollowCategoryNew,
-		FollowCategoryToiletPaper,
-		FollowCategoryMasterpiece,
+// ParseMediaType 根据字符串表示解析动漫类型（忽略大小写）
+func ParseMediaType(s string) (MediaType, bool) {
+	for _, mt := range AllMediaTypes() {
+		if strings.EqualFold(mt.String(), s) {
+			return mt, true
+		}
+	}
+	return MediaTypeUnknown, false
+}
+
+// AllMediaTypes 返回所有动漫类型
+func AllMediaTypes() []MediaType {
+	return []MediaType{
+		MediaTypeTV,
+		MediaTypeOVA,
+		MediaTypeONA,
+		MediaTypeSpecial,
+		MediaTypeWeb,
+	}
+}
+
+// CreditRole 职员或声优的担当
+type CreditRole int
+
+// 担当
+const (
+	CreditRoleDirector CreditRole = iota
+	CreditRoleScript
+	CreditRoleMusic
+	CreditRoleCharacterDesign
+	CreditRoleVoiceActor
+	CreditRoleUnknown = 999
+)
+
+// String 返回担当的字符串表示
+func (cr CreditRole) String() string {
+	if !cr.IsValid() {
+		return "unknown"
+	}
+	return [...]string{"director", "script", "music", "character_design", "voice_actor"}[cr]
+}
+
+// IsValid 检查担当是否合法
+func (cr CreditRole) IsValid() bool {
+	switch cr {
+	case CreditRoleDirector, CreditRoleScript, CreditRoleMusic, CreditRoleCharacterDesign, CreditRoleVoiceActor:
+		return true
+	default:
+		return false
+	}
+}
+
+// AllCreditRoles 返回所有担当
+func AllCreditRoles() []CreditRole {
+	return []CreditRole{
+		CreditRoleDirector,
+		CreditRoleScript,
+		CreditRoleMusic,
+		CreditRoleCharacterDesign,
+		CreditRoleVoiceActor,
 	}
 }
 
@@ -51,18 +143,231 @@ const (
 	FollowStatusWantToWatch FollowStatus = iota
 	FollowStatusWatching
 	FollowStatusWatched
+	FollowStatusOnHold
+	FollowStatusDropped
+	FollowStatusRewatching
 	FollowStatusUnknown = 999
 )
 
 func (fs FollowStatus) String() string {
-	return [...]string{"want_to_watch", "watching", "watched"}[fs]
+	if !fs.IsValid() {
+		return "unknown"
+	}
+	return [...]string{"want_to_watch", "watching", "watched", "on_hold", "dropped", "rewatching"}[fs]
 }
 
 func (fs FollowStatus) IsValid() bool {
 	switch fs {
-	case FollowStatusWantToWatch, FollowStatusWatching, FollowStatusWatched:
+	case FollowStatusWantToWatch, FollowStatusWatching, FollowStatusWatched,
+		FollowStatusOnHold, FollowStatusDropped, FollowStatusRewatching:
 		return true
 	default:
 		return false
 	}
 }
+
+// AllFollowStatuses 返回所有追番状态
+func AllFollowStatuses() []FollowStatus {
+	return []FollowStatus{
+		FollowStatusWantToWatch,
+		FollowStatusWatching,
+		FollowStatusWatched,
+		FollowStatusOnHold,
+		FollowStatusDropped,
+		FollowStatusRewatching,
+	}
+}
+
+// WatchedFollowStatuses 表示已经看完过的追番状态，重温中的追番也算看过
+var WatchedFollowStatuses = []FollowStatus{FollowStatusWatched, FollowStatusRewatching}
+
+// AiringStatus 动漫放送状态
+type AiringStatus int
+
+const (
+	AiringStatusUpcoming AiringStatus = iota
+	AiringStatusAiring
+	AiringStatusFinished
+	AiringStatusHiatus
+	AiringStatusCancelled
+	AiringStatusUnknown = 999
+)
+
+func (as AiringStatus) String() string {
+	if !as.IsValid() {
+		return "unknown"
+	}
+	return [...]string{"upcoming", "airing", "finished", "hiatus", "cancelled"}[as]
+}
+
+func (as AiringStatus) IsValid() bool {
+	switch as {
+	case AiringStatusUpcoming, AiringStatusAiring, AiringStatusFinished, AiringStatusHiatus, AiringStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseAiringStatus 根据字符串表示解析放送状态
+func ParseAiringStatus(s string) (AiringStatus, bool) {
+	for _, as := range AllAiringStatuses() {
+		if as.String() == s {
+			return as, true
+		}
+	}
+	return AiringStatusUnknown, false
+}
+
+// AllAiringStatuses 返回所有放送状态
+func AllAiringStatuses() []AiringStatus {
+	return []AiringStatus{
+		AiringStatusUpcoming,
+		AiringStatusAiring,
+		AiringStatusFinished,
+		AiringStatusHiatus,
+		AiringStatusCancelled,
+	}
+}
+
+// ExternalSource 外部数据库
+type ExternalSource string
+
+// 外部数据库
+const (
+	ExternalSourceBangumi     ExternalSource = "bangumi"
+	ExternalSourceMyAnimeList ExternalSource = "mal"
+	ExternalSourceAniList     ExternalSource = "anilist"
+)
+
+// IsValid 检查外部数据库是否合法
+func (es ExternalSource) IsValid() bool {
+	switch es {
+	case ExternalSourceBangumi, ExternalSourceMyAnimeList, ExternalSourceAniList:
+		return true
+	default:
+		return false
+	}
+}
+
+// URL 返回外部数据库中条目的链接
+func (es ExternalSource) URL(externalID string) string {
+	switch es {
+	case ExternalSourceBangumi:
+		return "https://bgm.tv/subject/" + externalID
+	case ExternalSourceMyAnimeList:
+		return "https://myanimelist.net/anime/" + externalID
+	case ExternalSourceAniList:
+		return "https://anilist.co/anime/" + externalID
+	default:
+		return ""
+	}
+}
+
+// AllExternalSources 返回所有外部数据库
+func AllExternalSources() []ExternalSource {
+	return []ExternalSource{
+		ExternalSourceBangumi,
+		ExternalSourceMyAnimeList,
+		ExternalSourceAniList,
+	}
+}
+
+// SynonymKind 同义词指向的实体类型
+type SynonymKind string
+
+// 同义词类型
+const (
+	SynonymKindTag      SynonymKind = "tag"
+	SynonymKindCategory SynonymKind = "category"
+)
+
+// IsValid 检查同义词类型是否合法
+func (sk SynonymKind) IsValid() bool {
+	switch sk {
+	case SynonymKindTag, SynonymKindCategory:
+		return true
+	default:
+		return false
+	}
+}
+
+// MaxHierarchyDepth 标签和分类层级的最大层数，顶层为第 1 层
+const MaxHierarchyDepth = 3
+
+// TagCardinality 标签组规则：一部动漫在该组中可以有几个标签
+type TagCardinality string
+
+// 标签组规则
+const (
+	TagCardinalityExactlyOne TagCardinality = "exactly_one"
+	TagCardinalityAtMostOne  TagCardinality = "at_most_one"
+	TagCardinalityMany       TagCardinality = "many"
+)
+
+// IsValid 检查标签组规则是否合法
+func (tc TagCardinality) IsValid() bool {
+	switch tc {
+	case TagCardinalityExactlyOne, TagCardinalityAtMostOne, TagCardinalityMany:
+		return true
+	default:
+		return false
+	}
+}
+
+// AllTagCardinalities 返回所有标签组规则
+func AllTagCardinalities() []TagCardinality {
+	return []TagCardinality{TagCardinalityExactlyOne, TagCardinalityAtMostOne, TagCardinalityMany}
+}
+
+// Allows 检查标签数量是否符合规则
+func (tc TagCardinality) Allows(count int) bool {
+	switch tc {
+	case TagCardinalityExactlyOne:
+		return count == 1
+	case TagCardinalityAtMostOne:
+		return count <= 1
+	default:
+		return true
+	}
+}
+
+// Role 用户角色，权限从低到高依次为 viewer、editor、admin
+type Role string
+
+// 用户角色
+const (
+	RoleAnonymous Role = "anonymous" // 未登录，只在路由权限表中使用
+	RoleViewer    Role = "viewer"    // 浏览目录、管理自己的追番
+	RoleEditor    Role = "editor"    // 编辑动漫目录
+	RoleAdmin     Role = "admin"     // 删除、合并、备份恢复和用户管理
+)
+
+// roleLevels 角色的权限等级
+var roleLevels = map[Role]int{
+	RoleAnonymous: 0,
+	RoleViewer:    1,
+	RoleEditor:    2,
+	RoleAdmin:     3,
+}
+
+// IsValid 检查用户角色是否合法（不包括 anonymous）
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleViewer, RoleEditor, RoleAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+// AllRoles 返回所有用户角色
+func AllRoles() []Role {
+	return []Role{RoleViewer, RoleEditor, RoleAdmin}
+}
+
+// Allows 检查角色是否拥有 required 要求的权限
+func (r Role) Allows(required Role) bool {
+	level, ok := roleLevels[r]
+	return ok && level >= roleLevels[required]
+}
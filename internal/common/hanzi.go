@@ -0,0 +1,47 @@
+package common
+
+import "strings"
+
+// traditionalPairs 常用繁体字与简体字对照，每两个字符为一组（繁体、简体），仅覆盖常见的一对一转换
+const traditionalPairs = "萬万與与醜丑專专業业叢丛東东絲丝兩两嚴严喪丧個个豐丰臨临為为麗丽舉举義义烏乌樂乐喬乔習习鄉乡書书買买亂乱爭争於于虧亏雲云" +
+	"亞亚產产親亲億亿僅仅從从倉仓儀仪們们價价眾众優优會会傘伞偉伟傳传傷伤倫伦偽伪體体餘余俠侠侶侣偵侦側侧僑侨儉俭債债傾倾僕仆" +
+	"儲储兒儿兌兑黨党蘭兰關关興兴養养獸兽內内岡冈冊册寫写軍军農农馮冯衝冲決决況况凍冻淨净涼凉減减湊凑幾几鳳凤憑凭凱凯擊击劃划" +
+	"劉刘則则剛刚創创刪删別别劍剑劑剂勸劝辦办務务動动勵励勁劲勞劳勢势勳勋區区醫医華华協协單单賣卖盧卢衛卫卻却廠厂廳厅曆历歷历" +
+	"厲厉壓压厭厌廁厕廂厢廈厦廚厨縣县參参雙双發发變变敘叙疊叠葉叶號号嘆叹後后嚇吓呂吕嗎吗噸吨聽听啟启吳吴員员嗚呜響响啞哑嘩哗" +
+	"喚唤噴喷團团園园圍围圖图圓圆聖圣場场壞坏塊块堅坚壇坛壩坝墳坟墜坠壘垒墮堕壯壮聲声殼壳壺壶處处備备復复夠够頭头誇夸夾夹奪夺" +
+	"奮奋獎奖奧奥妝妆婦妇媽妈嬌娇娛娱嬰婴孫孙學学寧宁寶宝實实寵宠審审憲宪宮宫寬宽賓宾寢寝對对尋寻導导壽寿將将爾尔塵尘盡尽層层" +
+	"屬属島岛嶺岭峽峡巒峦幣币帥帅師师帳帐簾帘幟帜帶带幫帮莊庄慶庆廬庐庫库應应廟庙龐庞廢废開开異异棄弃張张彌弥彎弯彈弹強强歸归" +
+	"當当錄录彥彦徹彻徑径憶忆憂忧懷怀態态憐怜總总戀恋懇恳惡恶惱恼悅悦懸悬驚惊懼惧慘惨懲惩慚惭慣惯憤愤願愿懶懒戲戏戰战戶户撲扑" +
+	"執执擴扩掃扫揚扬擾扰撫抚搶抢護护報报擔担擬拟擁拥攔拦撥拨擇择掛挂揮挥撈捞損损撿捡換换據据擺摆搖摇攤摊撐撑敵敌斂敛數数齋斋" +
+	"鬥斗斬斩斷断無无舊旧時时曠旷晝昼顯显晉晋曬晒曉晓暈晕暉晖暫暂術术機机殺杀雜杂權权條条來来楊杨傑杰極极構构棗枣槍枪楓枫櫃柜" +
+	"檸柠標标棟栋欄栏樹树棲栖樣样橋桥樺桦樁桩夢梦檢检樓楼欖榄橫横櫻樱欽钦歐欧殲歼殘残毆殴毀毁畢毕斃毙氣气氫氢匯汇漢汉湯汤溝沟" +
+	"沒没瀝沥淪沦滄沧滬沪淚泪瀉泻潑泼澤泽潔洁灑洒窪洼淺浅漿浆澆浇濁浊測测濟济瀏浏渾浑濃浓濤涛漣涟渦涡滌涤潤润澗涧漲涨淵渊漬渍" +
+	"漸渐漁渔滲渗溫温遊游灣湾濕湿潰溃濺溅滿满濾滤濫滥濱滨灘滩瀟潇潛潜瀾澜瀕濒滅灭燈灯靈灵災灾燦灿爐炉燉炖點点煉炼熾炽爍烁爛烂" +
+	"燭烛煙烟煩烦燒烧燙烫燼烬熱热煥焕愛爱爺爷牽牵犧牺狀状猶犹狽狈獨独狹狭獅狮獄狱獲获獵猎獻献貓猫瑪玛環环現现璽玺瓏珑瑣琐瓊琼" +
+	"瑤瑶瑩莹甕瓮電电畫画暢畅療疗瘋疯癢痒癡痴癱瘫癮瘾皚皑皺皱盞盏鹽盐監监蓋盖盤盘矚瞩瞼睑矯矫礦矿碼码磚砖硯砚礎础碩硕確确礙碍" +
+	"禮礼禍祸禎祯祿禄禪禅離离禿秃種种積积稱称穢秽穩稳窮穷竊窃竅窍窯窑竄窜窩窝窺窥豎竖競竞筆笔筍笋箏筝籌筹簽签簡简籃篮篩筛築筑" +
+	"篤笃簍篓籠笼糧粮類类糞粪緊紧糾纠紀纪約约紅红紋纹納纳紐纽純纯紗纱紙纸級级紛纷紡纺細细紳绅組组絆绊終终經经結结給给絡络絕绝" +
+	"統统絹绢綁绑繼继續续綠绿維维綱纲網网綴缀綿绵緒绪練练線线締缔編编緣缘緯纬縮缩織织繞绕繪绘繡绣罰罚罷罢羅罗聯联聰聪職职聶聂" +
+	"聾聋肅肃腸肠膚肤腦脑腳脚臉脸膽胆勝胜膠胶脈脉腫肿臟脏艦舰艙舱艱艰艷艳節节蘇苏範范藥药莖茎薦荐萊莱蓮莲蕭萧藍蓝薩萨藝艺蟲虫" +
+	"蝦虾蠶蚕蠻蛮螢萤補补裝装襪袜製制複复見见規规視视覺觉覽览觀观觸触計计訂订認认討讨讓让訓训議议記记講讲許许論论設设訪访證证" +
+	"評评識识詞词譯译試试詩诗誠诚話话該该詳详語语誤误說说請请讀读課课誰谁調调談谈謝谢謎谜貝贝負负財财責责貨货質质販贩貪贪貧贫" +
+	"購购貫贯貴贵費费貼贴賀贺資资賊贼賞赏賠赔賴赖贏赢贊赞趕赶趙赵躍跃踐践蹤踪車车軌轨軟软轉转輕轻載载較较輪轮輸输轟轰辭辞邊边" +
+	"遼辽達达遷迁過过運运還还這这進进遠远連连遲迟適适選选遺遗郵邮鄰邻醬酱釋释裡里裏里鑒鉴針针釣钓鈴铃鉛铅銀银銅铜鋼钢錢钱錯错" +
+	"鍵键鎮镇鏡镜鐘钟鐵铁長长門门閃闪閉闭問问閒闲間间閣阁閱阅闊阔隊队陽阳陰阴陣阵階阶際际陸陆險险隨随隱隐難难雞鸡霧雾靜静韓韩" +
+	"頁页頂顶項项順顺須须預预領领頻频題题額额顏颜顧顾風风飛飞飯饭飲饮飼饲飽饱飾饰館馆饅馒馬马駕驾駐驻騎骑騰腾驗验驅驱髮发鬆松" +
+	"魚鱼鮮鲜鳥鸟鳴鸣鴨鸭鶴鹤鷹鹰麥麦黃黄齊齐齒齿龍龙龜龟劇剧殭僵鏈链羈羁賽赛誌志"
+
+// simplifier 将繁体字转换为简体字
+var simplifier = func() *strings.Replacer {
+	runes := []rune(traditionalPairs)
+	oldnew := make([]string, 0, len(runes))
+	for _, r := range runes {
+		oldnew = append(oldnew, string(r))
+	}
+	return strings.NewReplacer(oldnew...)
+}()
+
+// ToSimplified 将常用繁体字转换为简体字，其余字符保持不变
+func ToSimplified(s string) string {
+	return simplifier.Replace(s)
+}
@@ -0,0 +1,85 @@
+package common
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// nameSeparators 名称列表中常见的分隔符
+const nameSeparators = ",，、/／×&＆;；"
+
+// SplitNames 按常见分隔符拆分名称列表，去除空白和重复项
+func SplitNames(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return strings.ContainsRune(nameSeparators, r)
+	})
+	names := make([]string, 0, len(fields))
+	seen := make(map[string]bool)
+	for _, field := range fields {
+		name := strings.TrimSpace(field)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// NormalizeName 归一化名称用于比较：统一全角半角、繁简体，转小写并去除空白和标点
+func NormalizeName(s string) string {
+	s = ToSimplified(norm.NFKC.String(s))
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// CanonicalName 返回标签、分类等名称的规范形式，用于判断不同写法是否指向同一实体；
+// 名称全部由标点组成时退回到去除首尾空白后的小写形式
+func CanonicalName(s string) string {
+	if key := NormalizeName(s); key != "" {
+		return key
+	}
+	return strings.ToLower(strings.TrimSpace(norm.NFKC.String(s)))
+}
+
+// NameSimilarity 计算两个名称归一化后的相似度，范围 0-1
+func NameSimilarity(a, b string) float64 {
+	return NormalizedSimilarity([]rune(NormalizeName(a)), []rune(NormalizeName(b)))
+}
+
+// NormalizedSimilarity 计算两个已经归一化的名称的相似度，范围 0-1，用于需要反复比较同一批名称的场景
+func NormalizedSimilarity(a, b []rune) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	longest := max(len(a), len(b))
+	return 1 - float64(levenshtein(a, b))/float64(longest)
+}
+
+// levenshtein 计算编辑距离
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
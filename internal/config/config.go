@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
@@ -19,6 +20,54 @@ type DatabaseConfig struct {
 
 var DBConfig DatabaseConfig
 
+// BackupConfig 定时备份配置
+type BackupConfig struct {
+	Enabled    bool
+	Dir        string // 备份目录
+	Schedule   string // cron 表达式（分 时 日 月 周）
+	KeepDaily  int    // 保留最近 N 天每天最新的一份备份
+	KeepWeekly int    // 保留最近 M 周每周最新的一份备份
+}
+
+var Backup BackupConfig
+
+// MetadataConfig 元数据提供者配置
+type MetadataConfig struct {
+	BangumiBaseURL string        // Bangumi API 地址，可以指向本地的替身服务器
+	UserAgent      string        // Bangumi 要求请求带有 User-Agent
+	Timeout        time.Duration // 请求超时时间
+}
+
+var Metadata MetadataConfig
+
+// RecategorizeRule 追番自动归类规则
+type RecategorizeRule struct {
+	Name           string   `mapstructure:"name"`
+	From           int      `mapstructure:"from"`            // 原追番分类
+	To             int      `mapstructure:"to"`              // 目标追番分类
+	AiringStatuses []string `mapstructure:"airing_statuses"` // 动漫处于这些放送状态时才移动
+}
+
+// RecategorizeConfig 追番自动归类配置
+type RecategorizeConfig struct {
+	Enabled       bool
+	Interval      time.Duration
+	DefaultTarget int
+	Rules         []RecategorizeRule
+}
+
+var Recategorize RecategorizeConfig
+
+// AuthConfig 登录和权限配置
+type AuthConfig struct {
+	JWTSecret     string        // 访问令牌（HS256）的签名密钥，为空时每次启动随机生成
+	AccessTTL     time.Duration // 访问令牌有效期
+	RefreshTTL    time.Duration // 刷新令牌有效期
+	AnonymousRead bool          // 是否允许未登录时读取动漫目录
+}
+
+var Auth AuthConfig
+
 func InitConfig() {
 	// 加载 .env 文件
 	err := godotenv.Load("./configs/.env")
@@ -35,6 +84,21 @@ func InitConfig() {
 		log.Fatalf("Error reading config file, %s", err)
 	}
 
+	viper.SetDefault("backup.enabled", false)
+	viper.SetDefault("backup.dir", "./backups")
+	viper.SetDefault("backup.schedule", "0 3 * * *")
+	viper.SetDefault("backup.keep_daily", 7)
+	viper.SetDefault("backup.keep_weekly", 4)
+	viper.SetDefault("metadata.bangumi.base_url", "https://api.bgm.tv")
+	viper.SetDefault("metadata.bangumi.user_agent", "kong-anime-go")
+	viper.SetDefault("metadata.timeout", "10s")
+	viper.SetDefault("recategorize.enabled", true)
+	viper.SetDefault("recategorize.interval", "24h")
+	viper.SetDefault("recategorize.default_target", 0)
+	viper.SetDefault("auth.access_ttl", "15m")
+	viper.SetDefault("auth.refresh_ttl", "720h")
+	viper.SetDefault("auth.anonymous_read", true)
+
 	viper.AutomaticEnv()
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
@@ -46,4 +110,34 @@ func InitConfig() {
 		DBName:   viper.GetString("database.dbname"),
 		Charset:  viper.GetString("database.charset"),
 	}
+
+	Backup = BackupConfig{
+		Enabled:    viper.GetBool("backup.enabled"),
+		Dir:        viper.GetString("backup.dir"),
+		Schedule:   viper.GetString("backup.schedule"),
+		KeepDaily:  viper.GetInt("backup.keep_daily"),
+		KeepWeekly: viper.GetInt("backup.keep_weekly"),
+	}
+
+	Metadata = MetadataConfig{
+		BangumiBaseURL: viper.GetString("metadata.bangumi.base_url"),
+		UserAgent:      viper.GetString("metadata.bangumi.user_agent"),
+		Timeout:        viper.GetDuration("metadata.timeout"),
+	}
+
+	Recategorize = RecategorizeConfig{
+		Enabled:       viper.GetBool("recategorize.enabled"),
+		Interval:      viper.GetDuration("recategorize.interval"),
+		DefaultTarget: viper.GetInt("recategorize.default_target"),
+	}
+	Auth = AuthConfig{
+		JWTSecret:     viper.GetString("auth.jwt_secret"),
+		AccessTTL:     viper.GetDuration("auth.access_ttl"),
+		RefreshTTL:    viper.GetDuration("auth.refresh_ttl"),
+		AnonymousRead: viper.GetBool("auth.anonymous_read"),
+	}
+
+	if err := viper.UnmarshalKey("recategorize.rules", &Recategorize.Rules); err != nil {
+		log.Fatalf("Error reading recategorize rules, %s", err)
+	}
 }
@@ -1,9 +1,14 @@
 package dao
 
 import (
+	"errors"
+	"fmt"
+
+	"kong-anime-go/internal/common"
 	"kong-anime-go/internal/dao/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // AnimeDAO 定义动漫DAO
@@ -16,6 +21,16 @@ func NewAnimeDAO(db *gorm.DB) *AnimeDAO {
 	return &AnimeDAO{db: db}
 }
 
+// WithTx 返回使用事务 tx 的DAO
+func (dao *AnimeDAO) WithTx(tx *gorm.DB) *AnimeDAO {
+	return &AnimeDAO{db: tx}
+}
+
+// Transaction 在事务中执行 fn，fn 返回错误时回滚
+func (dao *AnimeDAO) Transaction(fn func(tx *gorm.DB) error) error {
+	return dao.db.Transaction(fn)
+}
+
 // Create 创建一个新的动漫
 func (dao *AnimeDAO) Create(anime *models.Anime) error {
 	return dao.db.Create(anime).Error
@@ -31,28 +46,54 @@ func (dao *AnimeDAO) HardDelete(id uint) error {
 	return dao.db.Unscoped().Delete(&models.Anime{}, id).Error
 }
 
-// Update 更新动漫
+// Update 更新动漫（关联关系由对应的 Add/Clear 方法维护）
 func (dao *AnimeDAO) Update(anime *models.Anime) error {
-	return dao.db.Save(anime).Error
+	return dao.db.Omit(clause.Associations).Save(anime).Error
 }
 
 // GetByID 根据ID获取动漫
 func (dao *AnimeDAO) GetByID(id uint) (*models.Anime, error) {
 	var anime models.Anime
-	err := dao.db.Preload("Categories").Preload("Tags").First(&anime, id).Error
+	err := dao.db.Preload("Categories").Preload("Tags").Preload("Studios").Preload("ExternalIDs").First(&anime, id).Error
 	return &anime, err
 }
 
+// AnimeFilter 动漫列表筛选条件，字段为 nil 时不筛选
+type AnimeFilter struct {
+	AiringStatus *common.AiringStatus
+	MediaType    *common.MediaType
+	PersonID     *uint              // 参与的职员或声优
+	CreditRole   *common.CreditRole // 与 PersonID 一起使用，限定担当
+}
+
+func (f AnimeFilter) apply(query *gorm.DB) *gorm.DB {
+	if f.AiringStatus != nil {
+		query = query.Where("animes.airing_status = ?", *f.AiringStatus)
+	}
+	if f.MediaType != nil {
+		query = query.Where("animes.media_type = ?", *f.MediaType)
+	}
+	if f.PersonID != nil {
+		credits := query.Session(&gorm.Session{NewDB: true}).Model(&models.Credit{}).
+			Select("anime_id").Where("person_id = ?", *f.PersonID)
+		if f.CreditRole != nil {
+			credits = credits.Where("role = ?", *f.CreditRole)
+		}
+		query = query.Where("animes.id IN (?)", credits)
+	}
+	return query
+}
+
 // GetAllPaginated 获取分页的动漫列表
-func (dao *AnimeDAO) GetAllPaginated(page, pageSize int) ([]models.Anime, int64, error) {
+func (dao *AnimeDAO) GetAllPaginated(page, pageSize int, filter AnimeFilter) ([]models.Anime, int64, error) {
 	var animes []models.Anime
 	var total int64
 	offset := (page - 1) * pageSize
-	err := dao.db.Preload("Categories").Preload("Tags").
+	err := filter.apply(dao.db).Preload("Categories").Preload("Tags").Preload("Studios").Preload("ExternalIDs").
 		Order("id DESC").
 		Limit(pageSize).Offset(offset).
 		Find(&animes).Error
-	dao.db.Model(&models.Anime{}).Count(&total)
+	filter.apply(dao.db.Model(&models.Anime{})).Count(&total)
 	return animes, total, err
 }
 
@@ -68,14 +109,54 @@ func (dao *AnimeDAO) GetBySeason(season string, page, pageSize int) ([]models.An
 	return dao.getByCondition("season = ?", []any{season}, page, pageSize)
 }
 
-// GetByCategory 根据分类获取动漫
+// GetByCategory 根据分类获取动漫，包含所有子分类的动漫
 func (dao *AnimeDAO) GetByCategory(categoryName string, page, pageSize int) ([]models.Anime, int64, error) {
-	return dao.getByJoinCondition("categories.name = ?", categoryName, "anime_categories", "categories", "category_id", page, pageSize)
+	return dao.getByHierarchy("categories", categoryName, animeCategoriesRef, page, pageSize)
 }
 
-// GetByTag 根据标签获取动漫
+// GetByTag 根据标签获取动漫，包含所有子标签的动漫
 func (dao *AnimeDAO) GetByTag(tagName string, page, pageSize int) ([]models.Anime, int64, error) {
-	return dao.getByJoinCondition("tags.name = ?", tagName, "anime_tags", "tags", "tag_id", page, pageSize)
+	return dao.getByHierarchy("tags", tagName, animeTagsRef, page, pageSize)
+}
+
+// GetByStudio 根据制作公司获取动漫
+func (dao *AnimeDAO) GetByStudio(studioID uint, page, pageSize int) ([]models.Anime, int64, error) {
+	return dao.getByJoinCondition("studios.id = ?", studioID, "anime_studios", "studios", "studio_id", page, pageSize)
+}
+
+// GetNameIndex 获取所有动漫的名称、别名和季度，用于名称匹配
+func (dao *AnimeDAO) GetNameIndex() ([]models.Anime, error) {
+	var animes []models.Anime
+	err := dao.db.Select("id", "name", "aliases", "season").Find(&animes).Error
+	return animes, err
+}
+
+// GetByExternalID 根据外部数据库中的ID获取动漫，找不到时返回 nil
+func (dao *AnimeDAO) GetByExternalID(source common.ExternalSource, externalID string) (*models.Anime, error) {
+	var ext models.ExternalID
+	err := dao.db.Where("source = ? AND external_id = ?", source, externalID).First(&ext).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return dao.GetByID(ext.AnimeID)
+}
+
+// AddExternalID 为动漫添加外部数据库中的ID
+func (dao *AnimeDAO) AddExternalID(ext *models.ExternalID) error {
+	return dao.db.Create(ext).Error
+}
+
+// DeleteExternalIDs 删除动漫在指定外部数据库中的ID
+func (dao *AnimeDAO) DeleteExternalIDs(animeID uint, source common.ExternalSource) error {
+	return dao.db.Unscoped().Where("anime_id = ? AND source = ?", animeID, source).Delete(&models.ExternalID{}).Error
+}
+
+// ClearExternalIDs 删除动漫的所有外部数据库ID
+func (dao *AnimeDAO) ClearExternalIDs(animeID uint) error {
+	return dao.db.Unscoped().Where("anime_id = ?", animeID).Delete(&models.ExternalID{}).Error
 }
 
 // ClearCategories 清除动漫的所有分类
@@ -88,6 +169,98 @@ func (dao *AnimeDAO) ClearTags(animeID uint) error {
 	return dao.clearAssociations(animeID, "Tags")
 }
 
+// ClearStudios 清除动漫的所有制作公司
+func (dao *AnimeDAO) ClearStudios(animeID uint) error {
+	return dao.clearAssociations(animeID, "Studios")
+}
+
+// ClearCredits 删除动漫的所有职员和声优担当
+func (dao *AnimeDAO) ClearCredits(animeID uint) error {
+	return dao.db.Unscoped().Where("anime_id = ?", animeID).Delete(&models.Credit{}).Error
+}
+
+// MoveCredits 将动漫的担当移动到另一个动漫，目标动漫已有相同担当（人物、担当、角色都相同）时删除，返回移动和删除的数量
+func (dao *AnimeDAO) MoveCredits(fromID, toID uint) (moved, dropped int, err error) {
+	var from, to []models.Credit
+	if err := dao.db.Where("anime_id = ?", fromID).Find(&from).Error; err != nil {
+		return 0, 0, err
+	}
+	if err := dao.db.Where("anime_id = ?", toID).Find(&to).Error; err != nil {
+		return 0, 0, err
+	}
+	key := func(c models.Credit) string {
+		return fmt.Sprintf("%d|%d|%s", c.PersonID, c.Role, c.Character)
+	}
+	existing := make(map[string]bool, len(to))
+	for _, c := range to {
+		existing[key(c)] = true
+	}
+	for _, c := range from {
+		if existing[key(c)] {
+			err = dao.db.Unscoped().Delete(&models.Credit{}, c.ID).Error
+			dropped++
+		} else {
+			err = dao.db.Model(&models.Credit{}).Where("id = ?", c.ID).UpdateColumn("anime_id", toID).Error
+			existing[key(c)] = true
+			moved++
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	return moved, dropped, nil
+}
+
+// MoveExternalIDs 将动漫的外部数据库ID移动到另一个动漫，目标动漫在同一外部数据库中已有ID时删除，返回移动和删除的数量
+func (dao *AnimeDAO) MoveExternalIDs(fromID, toID uint) (moved, dropped int, err error) {
+	var from, to []models.ExternalID
+	if err := dao.db.Where("anime_id = ?", fromID).Find(&from).Error; err != nil {
+		return 0, 0, err
+	}
+	if err := dao.db.Where("anime_id = ?", toID).Find(&to).Error; err != nil {
+		return 0, 0, err
+	}
+	sources := make(map[common.ExternalSource]bool, len(to))
+	for _, ext := range to {
+		sources[ext.Source] = true
+	}
+	for _, ext := range from {
+		if sources[ext.Source] {
+			err = dao.db.Unscoped().Delete(&models.ExternalID{}, ext.ID).Error
+			dropped++
+		} else {
+			err = dao.db.Model(&models.ExternalID{}).Where("id = ?", ext.ID).UpdateColumn("anime_id", toID).Error
+			sources[ext.Source] = true
+			moved++
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	return moved, dropped, nil
+}
+
+// CreateMerge 创建合并记录
+func (dao *AnimeDAO) CreateMerge(merge *models.AnimeMerge) error {
+	return dao.db.Create(merge).Error
+}
+
+// GetMergesPaginated 获取分页的合并记录，targetID 不为 0 时只返回合并到该动漫的记录
+func (dao *AnimeDAO) GetMergesPaginated(targetID uint, page, pageSize int) ([]models.AnimeMerge, int64, error) {
+	var merges []models.AnimeMerge
+	var total int64
+	query := dao.db.Model(&models.AnimeMerge{})
+	if targetID != 0 {
+		query = query.Where("target_id = ?", targetID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	err := query.Order("id DESC").Limit(pageSize).Offset(offset).Find(&merges).Error
+	return merges, total, err
+}
+
 // AddCategory 为动漫添加分类
 func (dao *AnimeDAO) AddCategory(animeID, categoryID uint) error {
 	a := models.Anime{}
@@ -106,6 +279,15 @@ func (dao *AnimeDAO) AddTag(animeID, tagID uint) error {
 	return dao.db.Model(&a).Association("Tags").Append(&tag)
 }
 
+// AddStudio 为动漫添加制作公司
+func (dao *AnimeDAO) AddStudio(animeID, studioID uint) error {
+	a := models.Anime{}
+	a.ID = animeID
+	studio := models.Studio{}
+	studio.ID = studioID
+	return dao.db.Model(&a).Association("Studios").Append(&studio)
+}
+
 type SeasonCount struct {
 	Season string
 	Count  int
@@ -122,6 +304,70 @@ func (dao *AnimeDAO) GetAllSeasons() ([]SeasonCount, error) {
 	return seasons, err
 }
 
+type MediaTypeCount struct {
+	MediaType common.MediaType
+	Count     int
+}
+
+// GetMediaTypeCounts 获取符合筛选条件的各类型动漫数量（忽略类型筛选条件）
+func (dao *AnimeDAO) GetMediaTypeCounts(filter AnimeFilter) ([]MediaTypeCount, error) {
+	var counts []MediaTypeCount
+	filter.MediaType = nil
+	err := filter.apply(dao.db.Model(&models.Anime{})).
+		Select("media_type, COUNT(*) as count").
+		Group("media_type").
+		Scan(&counts).Error
+	return counts, err
+}
+
+type SeasonMediaTypeCount struct {
+	Season    string
+	MediaType common.MediaType
+	Count     int
+}
+
+// GetSeasonMediaTypeCounts 获取每个季节各类型的动漫数量
+func (dao *AnimeDAO) GetSeasonMediaTypeCounts() ([]SeasonMediaTypeCount, error) {
+	var counts []SeasonMediaTypeCount
+	err := dao.db.Model(&models.Anime{}).
+		Select("season, media_type, COUNT(*) as count").
+		Group("season, media_type").
+		Order("season").
+		Scan(&counts).Error
+	return counts, err
+}
+
+type SeasonAiringStatusCount struct {
+	Season       string
+	AiringStatus common.AiringStatus
+	Count        int
+}
+
+// GetSeasonAiringStatusCounts 获取每个季节各放送状态的动漫数量
+func (dao *AnimeDAO) GetSeasonAiringStatusCounts() ([]SeasonAiringStatusCount, error) {
+	var counts []SeasonAiringStatusCount
+	err := dao.db.Model(&models.Anime{}).
+		Select("season, airing_status, COUNT(*) as count").
+		Group("season, airing_status").
+		Order("season").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// GetAutoAiringStatusAnimes 获取放送状态需要自动推导的动漫（仅包含推导所需字段）
+func (dao *AnimeDAO) GetAutoAiringStatusAnimes() ([]models.Anime, error) {
+	var animes []models.Anime
+	err := dao.db.Select("id", "season", "episodes", "airing_status").
+		Where("airing_status_manual = ?", false).
+		Find(&animes).Error
+	return animes, err
+}
+
+// UpdateAiringStatus 更新动漫的放送状态
+func (dao *AnimeDAO) UpdateAiringStatus(animeID uint, status common.AiringStatus) error {
+	return dao.db.Model(&models.Anime{}).Where("id = ?", animeID).UpdateColumn("airing_status", status).Error
+}
+
 func (dao *AnimeDAO) GetFollowsByAnimeID(animeID uint) ([]models.Follow, int64, error) {
 	var follows []models.Follow
 	var total int64
@@ -134,7 +380,7 @@ func (dao *AnimeDAO) getByCondition(condition string, args []any, page, pageSize
 	var total int64
 	offset := (page - 1) * pageSize
 	err := dao.db.Where(condition, args...).
-		Preload("Categories").Preload("Tags").
+		Preload("Categories").Preload("Tags").Preload("Studios").Preload("ExternalIDs").
 		Order("id DESC").
 		Limit(pageSize).Offset(offset).
 		Find(&animes).Error
@@ -142,15 +388,15 @@ func (dao *AnimeDAO) getByCondition(condition string, args []any, page, pageSize
 	return animes, total, err
 }
 
-func (dao *AnimeDAO) getByJoinCondition(condition, value, joinTable, joinModel, joinField string, page, pageSize int) ([]models.Anime, int64, error) {
+func (dao *AnimeDAO) getByJoinCondition(condition string, value any, joinTable, joinModel, joinField string, page, pageSize int) ([]models.Anime, int64, error) {
 	var animes []models.Anime
 	var total int64
 	offset := (page - 1) * pageSize
 	err := dao.db.Joins("JOIN "+joinTable+" ON "+joinTable+".anime_id = animes.id").
 		Joins("JOIN "+joinModel+" ON "+joinModel+".id = "+joinTable+"."+joinField).
 		Where(condition, value).
-		Preload("Categories").Preload("Tags").
-		Order("id DESC").
+		Preload("Categories").Preload("Tags").Preload("Studios").Preload("ExternalIDs").
+		Order("animes.id DESC").
 		Limit(pageSize).Offset(offset).
 		Find(&animes).Error
 	dao.db.Model(&models.Anime{}).Joins("JOIN "+joinTable+" ON "+joinTable+".anime_id = animes.id").
@@ -159,6 +405,35 @@ func (dao *AnimeDAO) getByJoinCondition(condition, value, joinTable, joinModel,
 	return animes, total, err
 }
 
+// getByHierarchy 根据标签或分类名称获取动漫，包含所有后代关联的动漫，名称不存在时返回空列表
+func (dao *AnimeDAO) getByHierarchy(table, name string, ref joinTableRef, page, pageSize int) ([]models.Anime, int64, error) {
+	var item struct{ ID uint }
+	err := dao.db.Table(table).Select("id").Where("name = ? AND deleted_at IS NULL", name).Take(&item).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return []models.Anime{}, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	h, err := loadHierarchy(dao.db, table)
+	if err != nil {
+		return nil, 0, err
+	}
+	// 使用子查询而不是 JOIN，避免同时关联父项和子项的动漫重复出现
+	subQuery := dao.db.Table(ref.table).Select(ref.ownerCol).Where(ref.itemCol+" IN ?", h.Descendants(item.ID))
+	var animes []models.Anime
+	var total int64
+	if err := dao.db.Model(&models.Anime{}).Where("id IN (?)", subQuery).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err = dao.db.Where("id IN (?)", subQuery).
+		Preload("Categories").Preload("Tags").Preload("Studios").Preload("ExternalIDs").
+		Order("id DESC").
+		Limit(pageSize).Offset((page - 1) * pageSize).
+		Find(&animes).Error
+	return animes, total, err
+}
+
 func (dao *AnimeDAO) clearAssociations(animeID uint, association string) error {
 	a := models.Anime{}
 	a.ID = animeID
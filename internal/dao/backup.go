@@ -0,0 +1,80 @@
+package dao
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BackupDAO 定义备份和恢复使用的DAO
+type BackupDAO struct {
+	db *gorm.DB
+}
+
+// NewBackupDAO 创建备份DAO
+func NewBackupDAO(db *gorm.DB) *BackupDAO {
+	return &BackupDAO{db: db}
+}
+
+// Transaction 在事务中执行 fn，fn 返回错误时回滚
+func (dao *BackupDAO) Transaction(fn func(txDAO *BackupDAO) error) error {
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&BackupDAO{db: tx})
+	})
+}
+
+// FindAll 按ID顺序读取表中所有未删除的记录，dest 为模型切片的指针
+func (dao *BackupDAO) FindAll(dest any) error {
+	return dao.db.Order("id").Find(dest).Error
+}
+
+// FindJoin 读取关联表中的所有关联 (ownerCol, targetCol)
+func (dao *BackupDAO) FindJoin(table, ownerCol, targetCol string) ([][2]uint, error) {
+	var rows []struct {
+		Owner  uint
+		Target uint
+	}
+	err := dao.db.Table(table).
+		Select(ownerCol + " as owner, " + targetCol + " as target").
+		Order(ownerCol + ", " + targetCol).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	joins := make([][2]uint, len(rows))
+	for i, row := range rows {
+		joins[i] = [2]uint{row.Owner, row.Target}
+	}
+	return joins, nil
+}
+
+// Create 插入一条记录（不处理关联）
+func (dao *BackupDAO) Create(value any) error {
+	return dao.db.Omit(clause.Associations).Create(value).Error
+}
+
+// CreateJoin 插入一条关联
+func (dao *BackupDAO) CreateJoin(table, ownerCol, targetCol string, owner, target uint) error {
+	return dao.db.Table(table).Create(map[string]any{ownerCol: owner, targetCol: target}).Error
+}
+
+// SetParent 设置标签或分类的父项
+func (dao *BackupDAO) SetParent(table string, id, parentID uint) error {
+	return dao.db.Table(table).Where("id = ?", id).Update("parent_id", parentID).Error
+}
+
+// HardDeleteAll 硬删除模型对应表中的所有记录，用于在恢复前清除迁移时写入的默认数据
+func (dao *BackupDAO) HardDeleteAll(model any) error {
+	return dao.db.Unscoped().Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(model).Error
+}
+
+// First 按条件查找第一条记录（包含软删除的记录），找不到时返回 gorm.ErrRecordNotFound
+func (dao *BackupDAO) First(dest any, query string, args ...any) error {
+	return dao.db.Unscoped().Where(query, args...).Order("id").First(dest).Error
+}
+
+// Count 统计模型对应表中的记录数（包含软删除的记录）
+func (dao *BackupDAO) Count(model any) (int64, error) {
+	var count int64
+	err := dao.db.Unscoped().Model(model).Count(&count).Error
+	return count, err
+}
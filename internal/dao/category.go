@@ -1,6 +1,9 @@
 package dao
 
 import (
+	"errors"
+
+	"kong-anime-go/internal/common"
 	"kong-anime-go/internal/dao/models"
 
 	"gorm.io/gorm"
@@ -16,8 +19,27 @@ func NewCategoryDAO(db *gorm.DB) *CategoryDAO {
 	return &CategoryDAO{db: db}
 }
 
-// Create 创建一个新的分类
+// WithTx 返回使用事务 tx 的DAO
+func (dao *CategoryDAO) WithTx(tx *gorm.DB) *CategoryDAO {
+	return &CategoryDAO{db: tx}
+}
+
+// Transaction 在事务中执行 fn，fn 返回错误时回滚
+func (dao *CategoryDAO) Transaction(fn func(txDAO *CategoryDAO) error) error {
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		return fn(dao.WithTx(tx))
+	})
+}
+
+// Create 创建一个新的分类，未指定显示顺序时排在最后
 func (dao *CategoryDAO) Create(category *models.Category) error {
+	if category.SortOrder == 0 {
+		order, err := nextSortOrder(dao.db, "categories")
+		if err != nil {
+			return err
+		}
+		category.SortOrder = order
+	}
 	return dao.db.Create(category).Error
 }
 
@@ -28,10 +50,10 @@ func (dao *CategoryDAO) GetByID(id uint) (*models.Category, error) {
 	return &category, err
 }
 
-// GetAll 获取所有分类
+// GetAll 按显示顺序获取所有分类
 func (dao *CategoryDAO) GetAll() ([]models.Category, error) {
 	var categories []models.Category
-	err := dao.db.Find(&categories).Error
+	err := dao.db.Order("sort_order, id").Find(&categories).Error
 	return categories, err
 }
 
@@ -78,24 +100,75 @@ func (dao *CategoryDAO) CheckRelatedItems(id uint) (bool, error) {
 	return animeCount > 0 || movieCount > 0, nil
 }
 
-// GetCategoryStats 获取分类统计信息
-func (dao *CategoryDAO) GetCategoryStats() (map[string]int, error) {
-	var results []struct {
-		Name  string
-		Count int
+// GetCategoryStats 按显示顺序获取分类统计信息，父分类的数量包含所有子分类关联的动漫
+func (dao *CategoryDAO) GetCategoryStats() ([]DisplayStat, error) {
+	return rollUpStats(dao.db, "categories", animeCategoriesRef)
+}
+
+// SetSortOrders 按 ids 的顺序重新设置分类的显示顺序
+func (dao *CategoryDAO) SetSortOrders(ids []uint) error {
+	return setSortOrders(dao.db, "categories", ids)
+}
+
+// GetHierarchy 获取所有分类的父子关系
+func (dao *CategoryDAO) GetHierarchy() (*Hierarchy, error) {
+	return loadHierarchy(dao.db, "categories")
+}
+
+// ReparentChildren 将分类的直接子分类移动到 parentID 下，parentID 为 nil 时成为顶层分类
+func (dao *CategoryDAO) ReparentChildren(id uint, parentID *uint) error {
+	return reparentChildren(dao.db, "categories", id, parentID)
+}
+
+var (
+	animeCategoriesRef = joinTableRef{table: "anime_categories", ownerCol: "anime_id", itemCol: "category_id"}
+	movieCategoriesRef = joinTableRef{table: "movie_categories", ownerCol: "movie_id", itemCol: "category_id"}
+)
+
+// GetMergeImpact 统计将源分类合并到目标分类时受影响的动漫和电影数量
+func (dao *CategoryDAO) GetMergeImpact(sourceID, targetID uint) (*MergeImpact, error) {
+	return mergeImpact(dao.db, animeCategoriesRef, movieCategoriesRef, sourceID, targetID)
+}
+
+// MoveItems 将源分类关联的动漫和电影移动到目标分类，已关联目标分类的不会重复关联
+func (dao *CategoryDAO) MoveItems(sourceID, targetID uint) error {
+	if err := moveJoinRows(dao.db, animeCategoriesRef, sourceID, targetID); err != nil {
+		return err
 	}
-	err := dao.db.Table("categories").
-		Select("categories.name as name, COUNT(anime_categories.anime_id) as count").
-		Joins("JOIN anime_categories ON anime_categories.category_id = categories.id").
-		Group("categories.name").
-		Scan(&results).Error
-	if err != nil {
+	return moveJoinRows(dao.db, movieCategoriesRef, sourceID, targetID)
+}
+
+// GetByCanonicalName 根据名称解析分类：依次按名称精确匹配、按同义词匹配、按规范化后的名称匹配，
+// 用于将大小写、全角半角、繁简体不同的写法解析到同一个分类
+func (dao *CategoryDAO) GetByCanonicalName(name string) (*models.Category, error) {
+	category, err := dao.GetByName(name)
+	if err == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+		return category, err
+	}
+	canonicalName := common.CanonicalName(name)
+	var synonymTarget models.Category
+	err = findBySynonym(dao.db, "categories", common.SynonymKindCategory, canonicalName, &synonymTarget)
+	if err == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+		return &synonymTarget, err
+	}
+	var categories []models.Category
+	if err := dao.db.Order("id").Find(&categories).Error; err != nil {
 		return nil, err
 	}
-
-	stats := make(map[string]int)
-	for _, result := range results {
-		stats[result.Name] = result.Count
+	for i := range categories {
+		if common.CanonicalName(categories[i].Name) == canonicalName {
+			return &categories[i], nil
+		}
 	}
-	return stats, nil
+	return nil, gorm.ErrRecordNotFound
+}
+
+// MoveSynonyms 将源分类的同义词移动到目标分类，并将源分类的名称登记为目标分类的同义词
+func (dao *CategoryDAO) MoveSynonyms(source, target *models.Category) error {
+	return moveSynonyms(dao.db, common.SynonymKindCategory, source.ID, target.ID, source.Name, target.Name)
+}
+
+// DeleteSynonyms 删除指向该分类的所有同义词
+func (dao *CategoryDAO) DeleteSynonyms(id uint) error {
+	return deleteSynonyms(dao.db, common.SynonymKindCategory, id)
 }
@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"kong-anime-go/internal/common"
 	"kong-anime-go/internal/dao/models"
 
 	"gorm.io/gorm"
@@ -16,14 +17,27 @@ func NewFollowDAO(db *gorm.DB) *FollowDAO {
 	return &FollowDAO{db: db}
 }
 
+// WithTx 返回使用事务 tx 的DAO
+func (dao *FollowDAO) WithTx(tx *gorm.DB) *FollowDAO {
+	return &FollowDAO{db: tx}
+}
+
 // Create 创建一个新的追番
 func (dao *FollowDAO) Create(follow *models.Follow) error {
 	return dao.db.Create(follow).Error
 }
 
-// Delete 删除追番
-func (dao *FollowDAO) Delete(id uint) error {
-	return dao.db.Delete(&models.Follow{}, id).Error
+// Delete 删除用户的追番，追番不存在或属于其他用户时返回 gorm.ErrRecordNotFound
+// 追番按用户和动漫唯一，所以直接硬删除，以便之后重新追番
+func (dao *FollowDAO) Delete(userID, id uint) error {
+	result := dao.db.Unscoped().Where("user_id = ?", userID).Delete(&models.Follow{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
 
 // HardDelete 硬删除追番
@@ -36,30 +50,35 @@ func (dao *FollowDAO) Update(follow *models.Follow) error {
 	return dao.db.Save(follow).Error
 }
 
-// GetByID 根据ID获取追番
-func (dao *FollowDAO) GetByID(id uint) (*models.Follow, error) {
+// GetByID 根据ID获取用户的追番，属于其他用户的追番视为不存在
+func (dao *FollowDAO) GetByID(userID, id uint) (*models.Follow, error) {
 	var follow models.Follow
-	err := dao.db.Preload("Anime").First(&follow, id).Error
+	err := dao.db.Preload("Anime").Where("user_id = ?", userID).First(&follow, id).Error
 	return &follow, err
 }
 
-// GetByAnimeID 根据AnimeID获取追番
-func (dao *FollowDAO) GetByAnimeID(animeID uint) (*models.Follow, error) {
+// UpdateAnimeID 将追番关联到另一个动漫
+func (dao *FollowDAO) UpdateAnimeID(id, animeID uint) error {
+	return dao.db.Model(&models.Follow{}).Where("id = ?", id).UpdateColumn("anime_id", animeID).Error
+}
+
+// GetByAnimeID 根据AnimeID获取用户的追番
+func (dao *FollowDAO) GetByAnimeID(userID, animeID uint) (*models.Follow, error) {
 	var follow models.Follow
-	err := dao.db.Where("anime_id = ?", animeID).First(&follow).Error
+	err := dao.db.Where("user_id = ? AND anime_id = ?", userID, animeID).First(&follow).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, nil
 	}
 	return &follow, err
 }
 
-// GetAllPaginated 获取分页的追番列表
-func (dao *FollowDAO) GetAllPaginated(page, pageSize int, category int, status int, name *string, sorter *string) ([]models.Follow, int64, error) {
+// GetAllPaginated 获取用户分页的追番列表
+func (dao *FollowDAO) GetAllPaginated(userID uint, page, pageSize int, category int, status int, name *string, sorter *string) ([]models.Follow, int64, error) {
 	var follows []models.Follow
 	var total int64
 	offset := (page - 1) * pageSize
-	query := dao.db.Preload("Anime").Limit(pageSize).Offset(offset)
-	countQuery := dao.db.Model(&models.Follow{})
+	query := dao.db.Preload("Anime").Where("follows.user_id = ?", userID).Limit(pageSize).Offset(offset)
+	countQuery := dao.db.Model(&models.Follow{}).Where("follows.user_id = ?", userID)
 	if category != -1 {
 		query = query.Where("category = ?", category)
 		countQuery = countQuery.Where("category = ?", category)
@@ -85,7 +104,7 @@ func (dao *FollowDAO) GetAllPaginated(page, pageSize int, category int, status i
 	return follows, total, err
 }
 
-// GetFollowsByAnimeID 根据AnimeID获取追番
+// GetFollowsByAnimeID 根据AnimeID获取所有用户的追番
 func (dao *FollowDAO) GetFollowsByAnimeID(animeID uint) ([]models.Follow, int64, error) {
 	var follows []models.Follow
 	var total int64
@@ -97,3 +116,30 @@ func (dao *FollowDAO) GetFollowsByAnimeID(animeID uint) ([]models.Follow, int64,
 func (dao *FollowDAO) HardDeleteByAnimeID(animeID uint) error {
 	return dao.db.Unscoped().Where("anime_id = ?", animeID).Delete(&models.Follow{}).Error
 }
+
+// GetByCategory 获取指定追番分类的追番（预加载动漫），userID 为 nil 时包括所有用户的追番
+func (dao *FollowDAO) GetByCategory(userID *uint, category common.FollowCategory) ([]models.Follow, error) {
+	var follows []models.Follow
+	query := dao.db.Preload("Anime").Where("category = ?", category)
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+	err := query.Order("id").Find(&follows).Error
+	return follows, err
+}
+
+// UpdateCategory 更新追番分类
+func (dao *FollowDAO) UpdateCategory(id uint, category common.FollowCategory) error {
+	return dao.db.Model(&models.Follow{}).Where("id = ?", id).Update("category", category).Error
+}
+
+// FindInBatches 按批遍历用户的所有追番（预加载动漫及其分类、标签和外部ID），避免一次性加载到内存
+func (dao *FollowDAO) FindInBatches(userID uint, batchSize int, fn func(follows []models.Follow) error) error {
+	var follows []models.Follow
+	return dao.db.Preload("Anime").Where("user_id = ?", userID).
+		Preload("Anime.Categories").Preload("Anime.Tags").Preload("Anime.ExternalIDs").
+		Order("id").
+		FindInBatches(&follows, batchSize, func(tx *gorm.DB, batch int) error {
+			return fn(follows)
+		}).Error
+}
@@ -0,0 +1,122 @@
+package dao
+
+import (
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao/models"
+
+	"gorm.io/gorm"
+)
+
+// defaultFollowCategories 首次迁移时写入的追番分类，值与之前硬编码的分类一致
+var defaultFollowCategories = []models.FollowCategory{
+	{Value: common.FollowCategoryClassic, Name: "旧时代的残党", Description: "经典老番或长篇番"},
+	{Value: common.FollowCategoryHighQuality, Name: "我们仍未知道那天所看见的番剧的名字", Description: "听说高质量的番"},
+	{Value: common.FollowCategoryNew, Name: "新番妙妙屋", Description: "看新番导视比较感兴趣的番"},
+	{Value: common.FollowCategoryToiletPaper, Name: "厕纸", Description: "专门用来找乐子杀时间的番"},
+	{Value: common.FollowCategoryMasterpiece, Name: "神！", Description: "无需多言"},
+}
+
+// seedFollowCategories 写入默认的追番分类
+func seedFollowCategories(db *gorm.DB) error {
+	categories := make([]models.FollowCategory, len(defaultFollowCategories))
+	for i, category := range defaultFollowCategories {
+		category.SortOrder = i + 1
+		categories[i] = category
+	}
+	return db.Create(&categories).Error
+}
+
+// FollowCategoryDAO 定义追番分类DAO
+type FollowCategoryDAO struct {
+	db *gorm.DB
+}
+
+// NewFollowCategoryDAO 创建追番分类DAO
+func NewFollowCategoryDAO(db *gorm.DB) *FollowCategoryDAO {
+	return &FollowCategoryDAO{db: db}
+}
+
+// WithTx 返回使用事务 tx 的DAO
+func (dao *FollowCategoryDAO) WithTx(tx *gorm.DB) *FollowCategoryDAO {
+	return &FollowCategoryDAO{db: tx}
+}
+
+// Transaction 在事务中执行 fn，fn 返回错误时回滚
+func (dao *FollowCategoryDAO) Transaction(fn func(txDAO *FollowCategoryDAO) error) error {
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		return fn(dao.WithTx(tx))
+	})
+}
+
+// Create 创建一个新的追番分类，未指定显示顺序时排在最后
+func (dao *FollowCategoryDAO) Create(category *models.FollowCategory) error {
+	if category.SortOrder == 0 {
+		order, err := nextSortOrder(dao.db, "follow_categories")
+		if err != nil {
+			return err
+		}
+		category.SortOrder = order
+	}
+	return dao.db.Create(category).Error
+}
+
+// NextValue 返回新建追番分类使用的分类值，即现有最大值加一
+func (dao *FollowCategoryDAO) NextValue() (common.FollowCategory, error) {
+	var maxValue *int
+	err := dao.db.Model(&models.FollowCategory{}).
+		Where("value <> ?", common.FollowCategoryUnknown).
+		Select("MAX(value)").Scan(&maxValue).Error
+	if err != nil || maxValue == nil {
+		return 0, err
+	}
+	return common.FollowCategory(*maxValue + 1), nil
+}
+
+// GetByValue 根据分类值获取追番分类
+func (dao *FollowCategoryDAO) GetByValue(value common.FollowCategory) (*models.FollowCategory, error) {
+	var category models.FollowCategory
+	err := dao.db.Where("value = ?", value).First(&category).Error
+	return &category, err
+}
+
+// GetByName 根据名称获取追番分类
+func (dao *FollowCategoryDAO) GetByName(name string) (*models.FollowCategory, error) {
+	var category models.FollowCategory
+	err := dao.db.Where("name = ?", name).First(&category).Error
+	return &category, err
+}
+
+// GetAll 按显示顺序获取所有追番分类
+func (dao *FollowCategoryDAO) GetAll() ([]models.FollowCategory, error) {
+	var categories []models.FollowCategory
+	err := dao.db.Order("sort_order, value").Find(&categories).Error
+	return categories, err
+}
+
+// Update 更新追番分类
+func (dao *FollowCategoryDAO) Update(category *models.FollowCategory) error {
+	return dao.db.Save(category).Error
+}
+
+// HardDelete 硬删除追番分类，以便之后可以重新使用相同的分类值
+func (dao *FollowCategoryDAO) HardDelete(id uint) error {
+	return dao.db.Unscoped().Delete(&models.FollowCategory{}, id).Error
+}
+
+// CountFollows 统计使用该分类的追番数量
+func (dao *FollowCategoryDAO) CountFollows(value common.FollowCategory) (int64, error) {
+	var count int64
+	err := dao.db.Model(&models.Follow{}).Where("category = ?", value).Count(&count).Error
+	return count, err
+}
+
+// SetSortOrders 按 values 的顺序依次设置追番分类的显示顺序为 1、2、3……
+func (dao *FollowCategoryDAO) SetSortOrders(values []common.FollowCategory) error {
+	for i, value := range values {
+		err := dao.db.Model(&models.FollowCategory{}).Where("value = ?", value).Update("sort_order", i+1).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
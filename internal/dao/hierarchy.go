@@ -0,0 +1,170 @@
+package dao
+
+import (
+	"gorm.io/gorm"
+)
+
+// Hierarchy 标签或分类的父子关系
+type Hierarchy struct {
+	parents  map[uint]uint   // 子ID到父ID，根节点不在其中
+	children map[uint][]uint // 父ID到子ID列表
+}
+
+// loadHierarchy 读取 table 中所有未删除项的父子关系
+func loadHierarchy(db *gorm.DB, table string) (*Hierarchy, error) {
+	var nodes []struct {
+		ID       uint
+		ParentID *uint
+	}
+	err := db.Table(table).Select("id, parent_id").Where("deleted_at IS NULL").Order("id").Scan(&nodes).Error
+	if err != nil {
+		return nil, err
+	}
+	h := &Hierarchy{parents: make(map[uint]uint), children: make(map[uint][]uint)}
+	for _, node := range nodes {
+		if node.ParentID != nil {
+			h.parents[node.ID] = *node.ParentID
+			h.children[*node.ParentID] = append(h.children[*node.ParentID], node.ID)
+		}
+	}
+	return h, nil
+}
+
+// Children 返回 id 的直接子项
+func (h *Hierarchy) Children(id uint) []uint {
+	return h.children[id]
+}
+
+// Descendants 返回 id 及其所有后代的ID，id 在第一个
+func (h *Hierarchy) Descendants(id uint) []uint {
+	ids := []uint{id}
+	seen := map[uint]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range h.children[ids[i]] {
+			if !seen[child] {
+				seen[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids
+}
+
+// Ancestors 返回 id 的所有祖先，从父项到根
+func (h *Hierarchy) Ancestors(id uint) []uint {
+	var ids []uint
+	seen := map[uint]bool{id: true}
+	for parent, ok := h.parents[id]; ok && !seen[parent]; parent, ok = h.parents[parent] {
+		seen[parent] = true
+		ids = append(ids, parent)
+	}
+	return ids
+}
+
+// Depth 返回 id 所在的层级，根为第 1 层
+func (h *Hierarchy) Depth(id uint) int {
+	return len(h.Ancestors(id)) + 1
+}
+
+// Height 返回以 id 为根的子树的层数，没有子项时为 1
+func (h *Hierarchy) Height(id uint) int {
+	height := 1
+	seen := map[uint]bool{id: true}
+	level := []uint{id}
+	for {
+		var next []uint
+		for _, node := range level {
+			for _, child := range h.children[node] {
+				if !seen[child] {
+					seen[child] = true
+					next = append(next, child)
+				}
+			}
+		}
+		if len(next) == 0 {
+			return height
+		}
+		height++
+		level = next
+	}
+}
+
+// DisplayStat 标签或分类的统计信息，附带显示用的元数据
+type DisplayStat struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Color       string `json:"color"`
+	Icon        string `json:"icon"`
+	SortOrder   int    `json:"sort_order"`
+	Count       int    `json:"count"` // 关联的动漫数量，包含所有后代关联的动漫
+}
+
+// rollUpStats 统计每一项及其所有后代关联的动漫数量（同一部动漫只计一次），按显示顺序返回，没有关联动漫的项不在结果中
+func rollUpStats(db *gorm.DB, table string, ref joinTableRef) ([]DisplayStat, error) {
+	h, err := loadHierarchy(db, table)
+	if err != nil {
+		return nil, err
+	}
+	var items []DisplayStat
+	err = db.Table(table).
+		Select("id, name, description, color, icon, sort_order").
+		Where("deleted_at IS NULL").
+		Order("sort_order, id").
+		Scan(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		OwnerID uint
+		ItemID  uint
+	}
+	err = db.Table(ref.table).Select(ref.ownerCol + " AS owner_id, " + ref.itemCol + " AS item_id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	owners := make(map[uint]map[uint]bool)
+	for _, row := range rows {
+		for _, id := range append([]uint{row.ItemID}, h.Ancestors(row.ItemID)...) {
+			if owners[id] == nil {
+				owners[id] = make(map[uint]bool)
+			}
+			owners[id][row.OwnerID] = true
+		}
+	}
+	stats := make([]DisplayStat, 0, len(items))
+	for _, item := range items {
+		if item.Count = len(owners[item.ID]); item.Count > 0 {
+			stats = append(stats, item)
+		}
+	}
+	return stats, nil
+}
+
+// nextSortOrder 返回排在 table 最后所需的显示顺序
+func nextSortOrder(db *gorm.DB, table string) (int, error) {
+	var maxOrder *int
+	if err := db.Table(table).Select("MAX(sort_order)").Scan(&maxOrder).Error; err != nil {
+		return 0, err
+	}
+	if maxOrder == nil {
+		return 1, nil
+	}
+	return *maxOrder + 1, nil
+}
+
+// setSortOrders 按 ids 的顺序依次设置显示顺序为 1、2、3……
+func setSortOrders(db *gorm.DB, table string, ids []uint) error {
+	for i, id := range ids {
+		if err := db.Table(table).Where("id = ?", id).Update("sort_order", i+1).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reparentChildren 将 id 的直接子项移动到 parentID 下，parentID 为 nil 时子项成为根
+func reparentChildren(db *gorm.DB, table string, id uint, parentID *uint) error {
+	return db.Table(table).Where("parent_id = ?", id).Update("parent_id", parentID).Error
+}
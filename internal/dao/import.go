@@ -0,0 +1,56 @@
+package dao
+
+import (
+	"kong-anime-go/internal/dao/models"
+
+	"gorm.io/gorm"
+)
+
+// ImportDAO 定义导入任务DAO
+type ImportDAO struct {
+	db *gorm.DB
+}
+
+// NewImportDAO 创建导入任务DAO
+func NewImportDAO(db *gorm.DB) *ImportDAO {
+	return &ImportDAO{db: db}
+}
+
+// CreateJob 创建导入任务
+func (dao *ImportDAO) CreateJob(job *models.ImportJob) error {
+	return dao.db.Omit("Rows").Create(job).Error
+}
+
+// UpdateJob 更新导入任务（不包含单行结果）
+func (dao *ImportDAO) UpdateJob(job *models.ImportJob) error {
+	return dao.db.Omit("Rows").Save(job).Error
+}
+
+// CreateRows 批量保存单行结果
+func (dao *ImportDAO) CreateRows(rows []models.ImportRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return dao.db.CreateInBatches(rows, 100).Error
+}
+
+// GetJobByID 根据ID获取用户的导入任务（包含单行结果）
+func (dao *ImportDAO) GetJobByID(userID, id uint) (*models.ImportJob, error) {
+	var job models.ImportJob
+	err := dao.db.Preload("Rows", func(db *gorm.DB) *gorm.DB {
+		return db.Order("line")
+	}).Where("user_id = ?", userID).First(&job, id).Error
+	return &job, err
+}
+
+// GetJobsPaginated 获取用户分页的导入任务
+func (dao *ImportDAO) GetJobsPaginated(userID uint, page, pageSize int) ([]models.ImportJob, int64, error) {
+	var jobs []models.ImportJob
+	var total int64
+	offset := (page - 1) * pageSize
+	err := dao.db.Where("user_id = ?", userID).Order("id DESC").
+		Limit(pageSize).Offset(offset).
+		Find(&jobs).Error
+	dao.db.Model(&models.ImportJob{}).Where("user_id = ?", userID).Count(&total)
+	return jobs, total, err
+}
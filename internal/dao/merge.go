@@ -0,0 +1,62 @@
+package dao
+
+import (
+	"gorm.io/gorm"
+)
+
+// MergeImpact 合并标签或分类时受影响的项目数量
+type MergeImpact struct {
+	Animes          int64 `json:"animes"`           // 源关联的动漫数量
+	Movies          int64 `json:"movies"`           // 源关联的电影数量
+	DuplicateAnimes int64 `json:"duplicate_animes"` // 同时关联了源和目标的动漫数量，合并后去重
+	DuplicateMovies int64 `json:"duplicate_movies"` // 同时关联了源和目标的电影数量，合并后去重
+}
+
+// joinTableRef 多对多关联表，ownerCol 为动漫或电影的列，itemCol 为标签或分类的列
+type joinTableRef struct {
+	table    string
+	ownerCol string
+	itemCol  string
+}
+
+// countJoinRows 统计源关联的项目数量，以及其中已经关联了目标的数量
+func countJoinRows(db *gorm.DB, ref joinTableRef, sourceID, targetID uint) (total, duplicate int64, err error) {
+	err = db.Table(ref.table).Where(ref.itemCol+" = ?", sourceID).Count(&total).Error
+	if err != nil {
+		return 0, 0, err
+	}
+	err = db.Table(ref.table).
+		Where(ref.itemCol+" = ?", sourceID).
+		Where(ref.ownerCol+" IN (?)", db.Table(ref.table).Select(ref.ownerCol).Where(ref.itemCol+" = ?", targetID)).
+		Count(&duplicate).Error
+	return total, duplicate, err
+}
+
+// moveJoinRows 将源的关联移动到目标：先为尚未关联目标的项目插入目标关联，再删除所有源关联
+func moveJoinRows(db *gorm.DB, ref joinTableRef, sourceID, targetID uint) error {
+	err := db.Exec(
+		"INSERT INTO "+ref.table+" ("+ref.ownerCol+", "+ref.itemCol+") "+
+			"SELECT "+ref.ownerCol+", ? FROM "+ref.table+" WHERE "+ref.itemCol+" = ? "+
+			"AND "+ref.ownerCol+" NOT IN (SELECT "+ref.ownerCol+" FROM "+ref.table+" WHERE "+ref.itemCol+" = ?)",
+		targetID, sourceID, targetID,
+	).Error
+	if err != nil {
+		return err
+	}
+	return db.Exec("DELETE FROM "+ref.table+" WHERE "+ref.itemCol+" = ?", sourceID).Error
+}
+
+// mergeImpact 统计动漫和电影关联表中受合并影响的数量
+func mergeImpact(db *gorm.DB, animeRef, movieRef joinTableRef, sourceID, targetID uint) (*MergeImpact, error) {
+	var impact MergeImpact
+	var err error
+	impact.Animes, impact.DuplicateAnimes, err = countJoinRows(db, animeRef, sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	impact.Movies, impact.DuplicateMovies, err = countJoinRows(db, movieRef, sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return &impact, nil
+}
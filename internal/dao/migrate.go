@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"kong-anime-go/internal/common"
 	"kong-anime-go/internal/dao/models"
 
 	"gorm.io/gorm"
@@ -8,5 +9,112 @@ import (
 
 // Migrate 迁移数据库
 func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(&models.Anime{}, &models.Category{}, &models.Tag{}, &models.Movie{}, &models.Follow{})
+	if err := purgeDeletedFollows(db); err != nil {
+		return err
+	}
+	if err := backfillFollowUserIDs(db); err != nil {
+		return err
+	}
+	// 只在新建追番分类表时写入默认分类，之后即使用户删除了所有分类也不再写入
+	seedCategories := !db.Migrator().HasTable(&models.FollowCategory{})
+	// 只在新建动漫和制作公司的关联表时根据 Production 文本关联制作公司，之后的关联由动漫的创建和更新维护
+	linkStudios := !db.Migrator().HasTable("anime_studios")
+	err := db.AutoMigrate(&models.User{}, &models.RefreshToken{}, &models.Anime{}, &models.Category{}, &models.Tag{}, &models.Movie{}, &models.Follow{},
+		&models.RecategorizeRun{}, &models.RecategorizeLog{}, &models.Studio{},
+		&models.Person{}, &models.Credit{}, &models.ExternalID{},
+		&models.ImportJob{}, &models.ImportRow{}, &models.AnimeMerge{}, &models.Synonym{}, &models.TagGroup{},
+		&models.FollowCategory{})
+	if err != nil {
+		return err
+	}
+	if seedCategories {
+		if err := seedFollowCategories(db); err != nil {
+			return err
+		}
+	}
+	if err := ensureAdmin(db); err != nil {
+		return err
+	}
+	if linkStudios {
+		if _, err := LinkProductionStudios(db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// purgeDeletedFollows 硬删除已软删除的追番，之后追番按用户和动漫唯一，软删除的记录会妨碍建立唯一索引
+func purgeDeletedFollows(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.Follow{}) {
+		return nil
+	}
+	return db.Unscoped().Where("deleted_at IS NOT NULL").Delete(&models.Follow{}).Error
+}
+
+// backfillFollowUserIDs 将可为空的 user_id 列中的 NULL 改为 0（不属于任何用户），之后的迁移才能将该列改为 NOT NULL
+func backfillFollowUserIDs(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&models.Follow{}, "user_id") {
+		return nil
+	}
+	return db.Model(&models.Follow{}).Unscoped().Where("user_id IS NULL").UpdateColumn("user_id", 0).Error
+}
+
+// ensureAdmin 已有用户但没有管理员时（引入角色之前创建的用户），将最早创建的用户设为管理员
+func ensureAdmin(db *gorm.DB) error {
+	var admins int64
+	if err := db.Model(&models.User{}).Where("role = ?", common.RoleAdmin).Count(&admins).Error; err != nil {
+		return err
+	}
+	if admins > 0 {
+		return nil
+	}
+	var first models.User
+	err := db.Order("id").First(&first).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return db.Model(&first).UpdateColumn("role", common.RoleAdmin).Error
+}
+
+// LinkProductionStudios 根据 Production 文本为尚未关联制作公司的动漫关联制作公司，返回处理的动漫数量
+// 按名称或别名匹配已有制作公司，匹配不到时创建新的制作公司
+func LinkProductionStudios(db *gorm.DB) (int, error) {
+	var animes []models.Anime
+	err := db.Select("id", "production").
+		Where("production <> ''").
+		Where("NOT EXISTS (SELECT 1 FROM anime_studios WHERE anime_studios.anime_id = animes.id)").
+		Find(&animes).Error
+	if err != nil {
+		return 0, err
+	}
+
+	linked := 0
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for i := range animes {
+			for _, name := range common.SplitNames(animes[i].Production) {
+				studio, err := getOrCreateStudio(tx, name)
+				if err != nil {
+					return err
+				}
+				if err := tx.Model(&animes[i]).Association("Studios").Append(studio); err != nil {
+					return err
+				}
+			}
+			linked++
+		}
+		return nil
+	})
+	return linked, err
+}
+
+func getOrCreateStudio(db *gorm.DB, name string) (*models.Studio, error) {
+	studio, err := findStudioByNameOrAlias(db, name)
+	if err != nil || studio != nil {
+		return studio, err
+	}
+	studio = &models.Studio{Name: name}
+	return studio, db.Create(studio).Error
 }
@@ -1,18 +1,26 @@
 package models
 
 import (
+	"kong-anime-go/internal/common"
+
 	"gorm.io/gorm"
 )
 
 // Anime 动漫模型
 type Anime struct {
 	gorm.Model
-	Name       string     `gorm:"not null"`                    // 名称
-	Aliases    string     `gorm:"type:text"`                   // 别名(原名、昵称等)，用逗号隔开
-	Categories []Category `gorm:"many2many:anime_categories;"` // 分类 (热血、冒险、搞笑、奇幻等)
-	Tags       []Tag      `gorm:"many2many:anime_tags;"`       // 标签 (原创、漫改、游戏改、小说改、其他)
-	Production string     // 制作公司
-	Season     string     // 季度(包含年份)
-	Episodes   int        // 集数
-	Image      string     // 图片（可能存储为Base64）
+	Name               string              `gorm:"not null"`                    // 名称
+	Aliases            string              `gorm:"type:text"`                   // 别名(原名、昵称等)，用逗号隔开
+	Categories         []Category          `gorm:"many2many:anime_categories;"` // 分类 (热血、冒险、搞笑、奇幻等)
+	Tags               []Tag               `gorm:"many2many:anime_tags;"`       // 标签 (原创、漫改、游戏改、小说改、其他)
+	Studios            []Studio            `gorm:"many2many:anime_studios;"`    // 制作公司 (联合制作时有多个)
+	Production         string              // 制作公司（原始文本）
+	Season             string              // 季度(包含年份)
+	Episodes           int                 // 集数
+	Image              string              // 图片（可能存储为Base64）
+	MediaType          common.MediaType    `gorm:"index"` // 类型 (TV、OVA、ONA、特别篇、网络动画)
+	AiringStatus       common.AiringStatus `gorm:"index"` // 放送状态 (未开播、放送中、已完结、停播、腰斩)
+	AiringStatusManual bool                // 放送状态是否手动指定，手动指定后不再自动推导
+	ExternalIDs        []ExternalID        // 外部数据库中的ID
+	LockedFields       string              `gorm:"size:255"` // 锁定的字段，元数据补全、导入等自动写入时跳过，用逗号隔开
 }
@@ -7,7 +7,13 @@ import (
 // Category 分类模型
 type Category struct {
 	gorm.Model
-	Name   string  `gorm:"unique;not null"`
-	Animes []Anime `gorm:"many2many:anime_categories;" json:"omitempty"`
-	Movies []Movie `gorm:"many2many:movie_categories;" json:"omitempty"`
+	Name        string     `gorm:"unique;not null"`
+	Description string     `gorm:"type:text"`           // 说明
+	Color       string     `gorm:"size:16"`             // 显示颜色，如 #FF8800
+	Icon        string     `gorm:"size:64"`             // 图标标识，由前端解释
+	SortOrder   int        `gorm:"index"`               // 显示顺序，从小到大
+	ParentID    *uint      `gorm:"index"`               // 父分类，为空时是顶层分类
+	Children    []Category `gorm:"-" json:",omitempty"` // 子分类，仅在返回树形结构时填充
+	Animes      []Anime    `gorm:"many2many:anime_categories;" json:",omitempty"`
+	Movies      []Movie    `gorm:"many2many:movie_categories;" json:",omitempty"`
 }
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"kong-anime-go/internal/common"
+
+	"gorm.io/gorm"
+)
+
+// ExternalID 动漫在外部数据库（Bangumi、MyAnimeList、AniList）中的ID
+type ExternalID struct {
+	gorm.Model
+	AnimeID    uint                  `gorm:"not null;index"`                                      // 关联的动漫ID
+	Source     common.ExternalSource `gorm:"size:32;not null;uniqueIndex:idx_external_source_id"` // 外部数据库
+	ExternalID string                `gorm:"size:64;not null;uniqueIndex:idx_external_source_id"` // 外部数据库中的ID
+	URL        string                `gorm:"-"`                                                   // 外部数据库中的链接
+}
+
+// AfterFind 生成外部数据库中的链接
+func (e *ExternalID) AfterFind(tx *gorm.DB) error {
+	e.URL = e.Source.URL(e.ExternalID)
+	return nil
+}
@@ -10,9 +10,12 @@ import (
 // Follow 追番模型
 type Follow struct {
 	gorm.Model
-	AnimeID    uint                  // 关联的动漫ID
+	UserID     uint                  `gorm:"not null;default:0;uniqueIndex:idx_follow_user_anime"` // 所属用户ID，每个用户对同一部动漫只有一条追番，0 表示不属于任何用户
+	AnimeID    uint                  `gorm:"uniqueIndex:idx_follow_user_anime"`                    // 关联的动漫ID
 	Anime      Anime                 // 关联的动漫
-	Category   common.FollowCategory // 分类 (经典、高质量、新番、厕纸、神作)
-	Status     common.FollowStatus   // 状态 (想看、在看、看过)
+	Category   common.FollowCategory // 追番分类值，对应 follow_categories 表中的分类
+	Status     common.FollowStatus   // 状态 (想看、在看、看过、搁置、弃番、重温)
 	FinishedAt *time.Time            `gorm:"default:null"` // 看完时间
+	DroppedAt  *time.Time            `gorm:"default:null"` // 弃番时间
+	Score      *float64              `gorm:"default:null"` // 评分 (0-10)
 }
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"kong-anime-go/internal/common"
+
+	"gorm.io/gorm"
+)
+
+// FollowCategory 追番分类，追番中保存的是分类值 Value 而不是ID
+type FollowCategory struct {
+	gorm.Model
+	Value       common.FollowCategory `gorm:"uniqueIndex;not null"` // 分类值，创建后不可修改
+	Name        string                `gorm:"unique;not null"`      // 名称
+	Description string                `gorm:"type:text"`            // 说明
+	Color       string                `gorm:"size:16"`              // 显示颜色，如 #FF8800
+	SortOrder   int                   `gorm:"index"`                // 显示顺序，从小到大
+}
@@ -0,0 +1,15 @@
+package models
+
+// Health 健康检查结果
+type Health struct {
+	Status string                 `json:"status"` // ok 或 error
+	Time   string                 `json:"time"`
+	Checks map[string]HealthCheck `json:"checks"`
+}
+
+// HealthCheck 单项健康检查结果
+type HealthCheck struct {
+	Status  string `json:"status"` // ok 或 error
+	Error   string `json:"error,omitempty"`
+	Details any    `json:"details,omitempty"`
+}
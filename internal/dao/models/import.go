@@ -0,0 +1,34 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// ImportJob 导入任务
+type ImportJob struct {
+	gorm.Model
+	UserID   uint        `gorm:"index"` // 发起导入的用户ID，命令行导入时为 0
+	Kind     string      // 导入类型 (mal、csv)
+	FileName string      // 上传的文件名
+	DryRun   bool        // 是否仅预览，不写入数据
+	Status   string      // 任务状态 (pending、running、completed、failed)
+	Total    int         // 总行数
+	Created  int         // 新建的数量
+	Matched  int         // 匹配到已有动漫的数量
+	Skipped  int         // 跳过的数量
+	Failed   int         // 失败的数量
+	Error    string      `gorm:"type:text"` // 任务失败时的错误信息
+	Rows     []ImportRow `gorm:"foreignKey:JobID" json:",omitempty"`
+}
+
+// ImportRow 导入任务的单行结果
+type ImportRow struct {
+	gorm.Model
+	JobID    uint   `gorm:"index"` // 关联的导入任务ID
+	Line     int    // 行号（从1开始）
+	Title    string // 标题
+	Action   string // 处理结果 (created、matched、skipped、failed)
+	AnimeID  *uint  // 新建或匹配到的动漫ID
+	FollowID *uint  // 新建的追番ID
+	Message  string `gorm:"type:text"` // 说明
+}
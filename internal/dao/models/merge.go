@@ -0,0 +1,18 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// AnimeMerge 动漫合并记录（审计用），源动漫合并后被删除，其合并前的数据保存在 SourceSnapshot 中
+type AnimeMerge struct {
+	gorm.Model
+	TargetID        uint   `gorm:"not null;index"` // 合并到的动漫ID
+	SourceID        uint   `gorm:"not null"`       // 被合并（已删除）的动漫ID
+	SourceName      string // 被合并的动漫名称
+	SourceSnapshot  string `gorm:"type:longtext"` // 被合并的动漫合并前的 JSON（包含分类、标签等）
+	FollowStrategy  string // 同一用户两者都有追番时的处理方式 (target、source、latest)
+	KeptFollowID    *uint  // 合并后保留的追番ID（只涉及一个用户的追番时记录）
+	DeletedFollowID *uint  // 因冲突被删除的追番ID（只涉及一个用户的追番时记录）
+	Details         string `gorm:"type:text"` // 合并明细
+}
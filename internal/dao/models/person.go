@@ -0,0 +1,28 @@
+package models
+
+import (
+	"kong-anime-go/internal/common"
+
+	"gorm.io/gorm"
+)
+
+// Person 职员或声优模型
+type Person struct {
+	gorm.Model
+	Name    string   `gorm:"not null;index"` // 名称
+	Aliases string   `gorm:"type:text"`      // 别名(原名、外文名等)，用逗号隔开
+	Credits []Credit `json:",omitempty"`     // 参与的作品
+}
+
+// Credit 职员或声优在动漫或电影中的担当
+type Credit struct {
+	gorm.Model
+	PersonID  uint              `gorm:"not null;index"` // 关联的人物ID
+	Person    *Person           `json:",omitempty"`
+	AnimeID   *uint             `gorm:"index"` // 关联的动漫ID，与 MovieID 二选一
+	Anime     *Anime            `json:",omitempty"`
+	MovieID   *uint             `gorm:"index"` // 关联的电影ID，与 AnimeID 二选一
+	Movie     *Movie            `json:",omitempty"`
+	Role      common.CreditRole `gorm:"index"` // 担当 (导演、脚本、音乐、人物设计、声优)
+	Character string            // 配音的角色名，仅声优使用
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"kong-anime-go/internal/common"
+
+	"gorm.io/gorm"
+)
+
+// RecategorizeRun 追番自动归类的执行记录
+type RecategorizeRun struct {
+	gorm.Model
+	UserID  *uint             `gorm:"index"` // 手动执行的用户ID，定时执行时为空（处理所有用户的追番）
+	Trigger string            // 触发方式 (schedule、manual)
+	Changed int               // 变更的追番数量
+	Error   string            `gorm:"type:text"` // 执行失败时的错误信息
+	Logs    []RecategorizeLog `gorm:"foreignKey:RunID"`
+}
+
+// RecategorizeLog 追番自动归类的变更明细
+type RecategorizeLog struct {
+	gorm.Model
+	RunID        uint                  `gorm:"index"` // 关联的执行记录ID
+	UserID       uint                  `gorm:"index"` // 追番所属的用户ID
+	FollowID     uint                  // 变更的追番ID
+	AnimeID      uint                  // 追番关联的动漫ID
+	AnimeName    string                // 动漫名称
+	Rule         string                // 命中的规则名称
+	FromCategory common.FollowCategory // 原追番分类
+	ToCategory   common.FollowCategory // 目标追番分类
+}
@@ -0,0 +1,13 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// Studio 制作公司模型
+type Studio struct {
+	gorm.Model
+	Name    string  `gorm:"unique;not null"`                            // 名称
+	Aliases string  `gorm:"type:text"`                                  // 别名(简称、外文名等)，用逗号隔开
+	Animes  []Anime `gorm:"many2many:anime_studios;" json:",omitempty"` // 参与制作的动漫
+}
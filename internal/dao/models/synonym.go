@@ -0,0 +1,14 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// Synonym 标签或分类的同义词，将其他写法映射到规范的标签或分类
+type Synonym struct {
+	gorm.Model
+	Kind          string `gorm:"size:16;not null;uniqueIndex:idx_synonym_kind_canonical"`  // 类型 (tag、category)
+	Name          string `gorm:"not null"`                                                 // 同义词原文
+	CanonicalName string `gorm:"size:191;not null;uniqueIndex:idx_synonym_kind_canonical"` // 规范化后的同义词，用于匹配
+	TargetID      uint   `gorm:"not null;index"`                                           // 指向的标签或分类ID
+}
@@ -7,7 +7,14 @@ import (
 // Tag 标签模型
 type Tag struct {
 	gorm.Model
-	Name   string  `gorm:"unique;not null"`
-	Animes []Anime `gorm:"many2many:anime_tags;" json:"omitempty"`
-	Movies []Movie `gorm:"many2many:movie_tags;" json:"omitempty"`
+	Name        string  `gorm:"unique;not null"`
+	Description string  `gorm:"type:text"`           // 说明
+	Color       string  `gorm:"size:16"`             // 显示颜色，如 #FF8800
+	Icon        string  `gorm:"size:64"`             // 图标标识，由前端解释
+	SortOrder   int     `gorm:"index"`               // 显示顺序，从小到大
+	ParentID    *uint   `gorm:"index"`               // 父标签，为空时是顶层标签
+	GroupID     *uint   `gorm:"index"`               // 所属的标签组
+	Children    []Tag   `gorm:"-" json:",omitempty"` // 子标签，仅在返回树形结构时填充
+	Animes      []Anime `gorm:"many2many:anime_tags;" json:",omitempty"`
+	Movies      []Movie `gorm:"many2many:movie_tags;" json:",omitempty"`
 }
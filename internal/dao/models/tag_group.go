@@ -0,0 +1,16 @@
+package models
+
+import (
+	"kong-anime-go/internal/common"
+
+	"gorm.io/gorm"
+)
+
+// TagGroup 标签组，限制一部动漫在组内可以有几个标签（如“来源”组的原创、漫改、游戏改、小说改只能选一个）
+type TagGroup struct {
+	gorm.Model
+	Name        string                `gorm:"unique;not null"`                      // 名称
+	Cardinality common.TagCardinality `gorm:"size:16;not null"`                     // 规则 (exactly_one、at_most_one、many)
+	Description string                `gorm:"type:text"`                            // 说明
+	Tags        []Tag                 `gorm:"foreignKey:GroupID" json:",omitempty"` // 组内的标签
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"kong-anime-go/internal/common"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// User 用户，追番按用户区分，动漫目录所有用户共享
+type User struct {
+	gorm.Model
+	Username     string      `gorm:"size:64;unique;not null"` // 登录名
+	PasswordHash string      `gorm:"not null" json:"-"`       // bcrypt 密码哈希
+	DisplayName  string      // 显示名称
+	Role         common.Role `gorm:"size:16;not null;default:viewer"` // 角色 (viewer、editor、admin)
+}
+
+// RefreshToken 刷新令牌，只保存令牌的 SHA-256 哈希，使用一次后即被替换
+type RefreshToken struct {
+	gorm.Model
+	UserID    uint      `gorm:"index"`               // 关联的用户ID
+	TokenHash string    `gorm:"size:64;uniqueIndex"` // 令牌的 SHA-256 哈希（十六进制）
+	ExpiresAt time.Time `gorm:"index"`               // 过期时间
+}
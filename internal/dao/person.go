@@ -0,0 +1,122 @@
+package dao
+
+import (
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao/models"
+
+	"gorm.io/gorm"
+)
+
+// PersonDAO 定义职员和声优DAO
+type PersonDAO struct {
+	db *gorm.DB
+}
+
+// NewPersonDAO 创建职员和声优DAO
+func NewPersonDAO(db *gorm.DB) *PersonDAO {
+	return &PersonDAO{db: db}
+}
+
+// Create 创建一个新的人物
+func (dao *PersonDAO) Create(person *models.Person) error {
+	return dao.db.Create(person).Error
+}
+
+// GetByID 根据ID获取人物
+func (dao *PersonDAO) GetByID(id uint) (*models.Person, error) {
+	var person models.Person
+	err := dao.db.First(&person, id).Error
+	return &person, err
+}
+
+// GetAllPaginated 获取分页的人物列表，name 不为空时按名称和别名模糊查询
+func (dao *PersonDAO) GetAllPaginated(name string, page, pageSize int) ([]models.Person, int64, error) {
+	var persons []models.Person
+	var total int64
+	offset := (page - 1) * pageSize
+	query := dao.db.Model(&models.Person{})
+	if name != "" {
+		query = query.Where("name LIKE ? OR aliases LIKE ?", "%"+name+"%", "%"+name+"%")
+	}
+	query.Count(&total)
+	err := query.Order("id DESC").Limit(pageSize).Offset(offset).Find(&persons).Error
+	return persons, total, err
+}
+
+// Update 更新人物
+func (dao *PersonDAO) Update(person *models.Person) error {
+	return dao.db.Omit("Credits").Save(person).Error
+}
+
+// HardDelete 硬删除人物
+func (dao *PersonDAO) HardDelete(id uint) error {
+	return dao.db.Unscoped().Delete(&models.Person{}, id).Error
+}
+
+// CheckRelatedItems 检查人物是否有关联的担当
+func (dao *PersonDAO) CheckRelatedItems(id uint) (bool, error) {
+	var count int64
+	err := dao.db.Model(&models.Credit{}).Where("person_id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+// CreateCredit 创建担当
+func (dao *PersonDAO) CreateCredit(credit *models.Credit) error {
+	return dao.db.Create(credit).Error
+}
+
+// GetCreditByID 根据ID获取担当
+func (dao *PersonDAO) GetCreditByID(id uint) (*models.Credit, error) {
+	var credit models.Credit
+	err := dao.db.Preload("Person").Preload("Anime").Preload("Movie").First(&credit, id).Error
+	return &credit, err
+}
+
+// HardDeleteCredit 硬删除担当
+func (dao *PersonDAO) HardDeleteCredit(id uint) error {
+	return dao.db.Unscoped().Delete(&models.Credit{}, id).Error
+}
+
+// GetCreditsByPerson 获取人物参与的作品，role 为 nil 时不筛选担当
+func (dao *PersonDAO) GetCreditsByPerson(personID uint, role *common.CreditRole) ([]models.Credit, error) {
+	var credits []models.Credit
+	query := dao.db.Preload("Anime").Preload("Movie").Where("person_id = ?", personID)
+	if role != nil {
+		query = query.Where("role = ?", *role)
+	}
+	err := query.Order("id").Find(&credits).Error
+	return credits, err
+}
+
+// GetCreditsByAnime 获取动漫的职员和声优
+func (dao *PersonDAO) GetCreditsByAnime(animeID uint) ([]models.Credit, error) {
+	var credits []models.Credit
+	err := dao.db.Preload("Person").Where("anime_id = ?", animeID).Order("role, id").Find(&credits).Error
+	return credits, err
+}
+
+// PersonWatchedCount 人物在看过的追番中出现的次数
+type PersonWatchedCount struct {
+	PersonID uint              `json:"person_id"`
+	Name     string            `json:"name"`
+	Role     common.CreditRole `json:"role"`
+	Count    int               `json:"count"`
+}
+
+// GetTopInWatchedFollows 获取在用户看过的追番中出现最多的人物，role 为 nil 时统计所有担当
+func (dao *PersonDAO) GetTopInWatchedFollows(userID uint, role *common.CreditRole, limit int) ([]PersonWatchedCount, error) {
+	var results []PersonWatchedCount
+	query := dao.db.Table("credits").
+		Select("people.id as person_id, people.name as name, credits.role as role, COUNT(DISTINCT follows.anime_id) as count").
+		Joins("JOIN people ON people.id = credits.person_id AND people.deleted_at IS NULL").
+		Joins("JOIN follows ON follows.anime_id = credits.anime_id AND follows.deleted_at IS NULL").
+		Where("credits.deleted_at IS NULL AND follows.user_id = ? AND follows.status IN ?", userID, common.WatchedFollowStatuses)
+	if role != nil {
+		query = query.Where("credits.role = ?", *role)
+	}
+	err := query.Group("people.id, people.name, credits.role").
+		Order("count DESC").
+		Limit(limit).
+		Scan(&results).Error
+	return results, err
+}
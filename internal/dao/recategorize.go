@@ -0,0 +1,54 @@
+package dao
+
+import (
+	"kong-anime-go/internal/dao/models"
+
+	"gorm.io/gorm"
+)
+
+// RecategorizeDAO 定义追番自动归类记录DAO
+type RecategorizeDAO struct {
+	db *gorm.DB
+}
+
+// NewRecategorizeDAO 创建追番自动归类记录DAO
+func NewRecategorizeDAO(db *gorm.DB) *RecategorizeDAO {
+	return &RecategorizeDAO{db: db}
+}
+
+// WithTx 返回使用事务 tx 的DAO
+func (dao *RecategorizeDAO) WithTx(tx *gorm.DB) *RecategorizeDAO {
+	return &RecategorizeDAO{db: tx}
+}
+
+// Transaction 在事务中执行 fn，fn 返回错误时回滚
+func (dao *RecategorizeDAO) Transaction(fn func(tx *gorm.DB) error) error {
+	return dao.db.Transaction(fn)
+}
+
+// CreateRun 创建执行记录（连同变更明细）
+func (dao *RecategorizeDAO) CreateRun(run *models.RecategorizeRun) error {
+	return dao.db.Create(run).Error
+}
+
+// GetRunByID 根据ID获取用户手动执行或定时执行的记录，只包含该用户追番的变更明细
+func (dao *RecategorizeDAO) GetRunByID(userID, id uint) (*models.RecategorizeRun, error) {
+	var run models.RecategorizeRun
+	err := dao.db.Preload("Logs", "user_id = ?", userID).
+		Where("user_id = ? OR user_id IS NULL", userID).
+		First(&run, id).Error
+	return &run, err
+}
+
+// GetRunsPaginated 获取用户手动执行和定时执行的分页执行记录
+func (dao *RecategorizeDAO) GetRunsPaginated(userID uint, page, pageSize int) ([]models.RecategorizeRun, int64, error) {
+	var runs []models.RecategorizeRun
+	var total int64
+	offset := (page - 1) * pageSize
+	err := dao.db.Where("user_id = ? OR user_id IS NULL", userID).
+		Order("id DESC").
+		Limit(pageSize).Offset(offset).
+		Find(&runs).Error
+	dao.db.Model(&models.RecategorizeRun{}).Where("user_id = ? OR user_id IS NULL", userID).Count(&total)
+	return runs, total, err
+}
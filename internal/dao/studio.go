@@ -0,0 +1,155 @@
+package dao
+
+import (
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao/models"
+
+	"gorm.io/gorm"
+)
+
+// StudioDAO 定义制作公司DAO
+type StudioDAO struct {
+	db *gorm.DB
+}
+
+// NewStudioDAO 创建制作公司DAO
+func NewStudioDAO(db *gorm.DB) *StudioDAO {
+	return &StudioDAO{db: db}
+}
+
+// WithTx 返回使用事务 tx 的DAO
+func (dao *StudioDAO) WithTx(tx *gorm.DB) *StudioDAO {
+	return &StudioDAO{db: tx}
+}
+
+// Transaction 在事务中执行 fn，fn 返回错误时回滚
+func (dao *StudioDAO) Transaction(fn func(txDAO *StudioDAO) error) error {
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		return fn(dao.WithTx(tx))
+	})
+}
+
+// Create 创建一个新的制作公司
+func (dao *StudioDAO) Create(studio *models.Studio) error {
+	return dao.db.Create(studio).Error
+}
+
+// GetByID 根据ID获取制作公司
+func (dao *StudioDAO) GetByID(id uint) (*models.Studio, error) {
+	var studio models.Studio
+	err := dao.db.First(&studio, id).Error
+	return &studio, err
+}
+
+// GetAll 获取所有制作公司
+func (dao *StudioDAO) GetAll() ([]models.Studio, error) {
+	var studios []models.Studio
+	err := dao.db.Find(&studios).Error
+	return studios, err
+}
+
+// Update 更新制作公司
+func (dao *StudioDAO) Update(studio *models.Studio) error {
+	return dao.db.Save(studio).Error
+}
+
+// HardDelete 硬删除制作公司
+func (dao *StudioDAO) HardDelete(id uint) error {
+	return dao.db.Unscoped().Delete(&models.Studio{}, id).Error
+}
+
+// GetByNameLike 根据名称或别名模糊查询制作公司
+func (dao *StudioDAO) GetByNameLike(name string) ([]models.Studio, error) {
+	var studios []models.Studio
+	err := dao.db.Where("name LIKE ? OR aliases LIKE ?", "%"+name+"%", "%"+name+"%").Find(&studios).Error
+	return studios, err
+}
+
+// FindByNameOrAlias 根据名称或别名精确查找制作公司（忽略大小写），找不到时返回 nil
+func (dao *StudioDAO) FindByNameOrAlias(name string) (*models.Studio, error) {
+	return findStudioByNameOrAlias(dao.db, name)
+}
+
+// GetOrCreate 根据名称或别名获取制作公司，不存在时创建
+func (dao *StudioDAO) GetOrCreate(name string) (*models.Studio, error) {
+	return getOrCreateStudio(dao.db, name)
+}
+
+// CheckRelatedItems 检查制作公司是否关联了动漫
+func (dao *StudioDAO) CheckRelatedItems(id uint) (bool, error) {
+	var count int64
+	err := dao.db.Table("anime_studios").Where("studio_id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+var animeStudiosRef = joinTableRef{table: "anime_studios", ownerCol: "anime_id", itemCol: "studio_id"}
+
+// GetMergeImpact 统计将源制作公司合并到目标制作公司时受影响的动漫数量
+func (dao *StudioDAO) GetMergeImpact(sourceID, targetID uint) (*MergeImpact, error) {
+	var impact MergeImpact
+	var err error
+	impact.Animes, impact.DuplicateAnimes, err = countJoinRows(dao.db, animeStudiosRef, sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return &impact, nil
+}
+
+// MoveAnimes 将源制作公司关联的动漫移动到目标制作公司，已关联目标制作公司的不会重复关联
+func (dao *StudioDAO) MoveAnimes(sourceID, targetID uint) error {
+	return moveJoinRows(dao.db, animeStudiosRef, sourceID, targetID)
+}
+
+// StudioStats 制作公司统计信息
+type StudioStats struct {
+	AnimeCount   int      `json:"anime_count"`   // 动漫数量
+	FollowCount  int      `json:"follow_count"`  // 追番数量
+	WatchedCount int      `json:"watched_count"` // 看过的数量
+	AverageScore *float64 `json:"average_score"` // 平均评分，没有评分时为空
+	WatchedRatio float64  `json:"watched_ratio"` // 看过的动漫占比
+}
+
+// GetStudioStats 获取制作公司统计信息，追番相关的数量统计所有用户的追番
+func (dao *StudioDAO) GetStudioStats(id uint) (*StudioStats, error) {
+	var stats StudioStats
+	err := dao.db.Table("anime_studios").
+		Select("COUNT(DISTINCT anime_studios.anime_id) as anime_count, "+
+			"COUNT(follows.id) as follow_count, "+
+			"COUNT(DISTINCT CASE WHEN follows.status IN ? THEN follows.anime_id END) as watched_count, "+
+			"AVG(follows.score) as average_score", common.WatchedFollowStatuses).
+		Joins("LEFT JOIN follows ON follows.anime_id = anime_studios.anime_id AND follows.deleted_at IS NULL").
+		Where("anime_studios.studio_id = ?", id).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	if stats.AnimeCount > 0 {
+		stats.WatchedRatio = float64(stats.WatchedCount) / float64(stats.AnimeCount)
+	}
+	return &stats, nil
+}
+
+// findStudioByNameOrAlias 按规范化后的名称查找制作公司，名称优先于别名（"Kyoto Animation" 与 "kyoto-animation" 视为同一写法）
+func findStudioByNameOrAlias(db *gorm.DB, name string) (*models.Studio, error) {
+	key := common.CanonicalName(name)
+	if key == "" {
+		return nil, nil
+	}
+	var studios []models.Studio
+	if err := db.Order("id").Find(&studios).Error; err != nil {
+		return nil, err
+	}
+	for i := range studios {
+		if common.CanonicalName(studios[i].Name) == key {
+			return &studios[i], nil
+		}
+	}
+	for i := range studios {
+		for _, alias := range common.SplitNames(studios[i].Aliases) {
+			if common.CanonicalName(alias) == key {
+				return &studios[i], nil
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,94 @@
+package dao
+
+import (
+	"errors"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao/models"
+
+	"gorm.io/gorm"
+)
+
+// SynonymDAO 定义同义词DAO
+type SynonymDAO struct {
+	db *gorm.DB
+}
+
+// NewSynonymDAO 创建同义词DAO
+func NewSynonymDAO(db *gorm.DB) *SynonymDAO {
+	return &SynonymDAO{db: db}
+}
+
+// Create 创建一个新的同义词
+func (dao *SynonymDAO) Create(synonym *models.Synonym) error {
+	return dao.db.Create(synonym).Error
+}
+
+// GetByID 根据ID获取同义词
+func (dao *SynonymDAO) GetByID(id uint) (*models.Synonym, error) {
+	var synonym models.Synonym
+	err := dao.db.First(&synonym, id).Error
+	return &synonym, err
+}
+
+// GetByCanonicalName 根据类型和规范化名称获取同义词
+func (dao *SynonymDAO) GetByCanonicalName(kind common.SynonymKind, canonicalName string) (*models.Synonym, error) {
+	var synonym models.Synonym
+	err := dao.db.Where("kind = ? AND canonical_name = ?", kind, canonicalName).First(&synonym).Error
+	return &synonym, err
+}
+
+// GetAll 获取同义词，kind 为空时返回所有类型，targetID 为 0 时不按目标过滤
+func (dao *SynonymDAO) GetAll(kind common.SynonymKind, targetID uint) ([]models.Synonym, error) {
+	var synonyms []models.Synonym
+	query := dao.db.Order("kind, target_id, id")
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if targetID != 0 {
+		query = query.Where("target_id = ?", targetID)
+	}
+	err := query.Find(&synonyms).Error
+	return synonyms, err
+}
+
+// Delete 硬删除同义词，以便之后可以重新创建相同的同义词
+func (dao *SynonymDAO) Delete(id uint) error {
+	return dao.db.Unscoped().Delete(&models.Synonym{}, id).Error
+}
+
+// findBySynonym 通过同义词查找标签或分类，table 为标签或分类的表名
+func findBySynonym(db *gorm.DB, table string, kind common.SynonymKind, canonicalName string, dest any) error {
+	return db.Joins("JOIN synonyms ON synonyms.target_id = "+table+".id").
+		Where("synonyms.kind = ? AND synonyms.canonical_name = ?", kind, canonicalName).
+		First(dest).Error
+}
+
+// moveSynonyms 合并时将指向源的同义词改为指向目标，并把源的名称登记为目标的同义词，
+// 使之后使用源名称时仍能解析到目标
+func moveSynonyms(db *gorm.DB, kind common.SynonymKind, sourceID, targetID uint, sourceName, targetName string) error {
+	err := db.Model(&models.Synonym{}).
+		Where("kind = ? AND target_id = ?", kind, sourceID).
+		Update("target_id", targetID).Error
+	if err != nil {
+		return err
+	}
+	canonicalName := common.CanonicalName(sourceName)
+	if canonicalName == common.CanonicalName(targetName) {
+		return nil
+	}
+	var existing models.Synonym
+	err = db.Where("kind = ? AND canonical_name = ?", kind, canonicalName).First(&existing).Error
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return db.Create(&models.Synonym{Kind: string(kind), Name: sourceName, CanonicalName: canonicalName, TargetID: targetID}).Error
+}
+
+// deleteSynonyms 硬删除指向标签或分类的所有同义词
+func deleteSynonyms(db *gorm.DB, kind common.SynonymKind, targetID uint) error {
+	return db.Unscoped().Where("kind = ? AND target_id = ?", kind, targetID).Delete(&models.Synonym{}).Error
+}
@@ -1,6 +1,9 @@
 package dao
 
 import (
+	"errors"
+
+	"kong-anime-go/internal/common"
 	"kong-anime-go/internal/dao/models"
 
 	"gorm.io/gorm"
@@ -16,8 +19,27 @@ func NewTagDAO(db *gorm.DB) *TagDAO {
 	return &TagDAO{db: db}
 }
 
-// Create 创建一个新的标签
+// WithTx 返回使用事务 tx 的DAO
+func (dao *TagDAO) WithTx(tx *gorm.DB) *TagDAO {
+	return &TagDAO{db: tx}
+}
+
+// Transaction 在事务中执行 fn，fn 返回错误时回滚
+func (dao *TagDAO) Transaction(fn func(txDAO *TagDAO) error) error {
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		return fn(dao.WithTx(tx))
+	})
+}
+
+// Create 创建一个新的标签，未指定显示顺序时排在最后
 func (dao *TagDAO) Create(tag *models.Tag) error {
+	if tag.SortOrder == 0 {
+		order, err := nextSortOrder(dao.db, "tags")
+		if err != nil {
+			return err
+		}
+		tag.SortOrder = order
+	}
 	return dao.db.Create(tag).Error
 }
 
@@ -28,10 +50,10 @@ func (dao *TagDAO) GetByID(id uint) (*models.Tag, error) {
 	return &tag, err
 }
 
-// GetAll 获取所有标签
+// GetAll 按显示顺序获取所有标签
 func (dao *TagDAO) GetAll() ([]models.Tag, error) {
 	var tags []models.Tag
-	err := dao.db.Find(&tags).Error
+	err := dao.db.Order("sort_order, id").Find(&tags).Error
 	return tags, err
 }
 
@@ -78,24 +100,75 @@ func (dao *TagDAO) CheckRelatedItems(id uint) (bool, error) {
 	return animeCount > 0 || movieCount > 0, nil
 }
 
-// GetTagStats 获取标签统计信息
-func (dao *TagDAO) GetTagStats() (map[string]int, error) {
-	var results []struct {
-		Name  string
-		Count int
+// GetTagStats 按显示顺序获取标签统计信息，父标签的数量包含所有子标签关联的动漫
+func (dao *TagDAO) GetTagStats() ([]DisplayStat, error) {
+	return rollUpStats(dao.db, "tags", animeTagsRef)
+}
+
+// SetSortOrders 按 ids 的顺序重新设置标签的显示顺序
+func (dao *TagDAO) SetSortOrders(ids []uint) error {
+	return setSortOrders(dao.db, "tags", ids)
+}
+
+// GetHierarchy 获取所有标签的父子关系
+func (dao *TagDAO) GetHierarchy() (*Hierarchy, error) {
+	return loadHierarchy(dao.db, "tags")
+}
+
+// ReparentChildren 将标签的直接子标签移动到 parentID 下，parentID 为 nil 时成为顶层标签
+func (dao *TagDAO) ReparentChildren(id uint, parentID *uint) error {
+	return reparentChildren(dao.db, "tags", id, parentID)
+}
+
+var (
+	animeTagsRef = joinTableRef{table: "anime_tags", ownerCol: "anime_id", itemCol: "tag_id"}
+	movieTagsRef = joinTableRef{table: "movie_tags", ownerCol: "movie_id", itemCol: "tag_id"}
+)
+
+// GetMergeImpact 统计将源标签合并到目标标签时受影响的动漫和电影数量
+func (dao *TagDAO) GetMergeImpact(sourceID, targetID uint) (*MergeImpact, error) {
+	return mergeImpact(dao.db, animeTagsRef, movieTagsRef, sourceID, targetID)
+}
+
+// MoveItems 将源标签关联的动漫和电影移动到目标标签，已关联目标标签的不会重复关联
+func (dao *TagDAO) MoveItems(sourceID, targetID uint) error {
+	if err := moveJoinRows(dao.db, animeTagsRef, sourceID, targetID); err != nil {
+		return err
 	}
-	err := dao.db.Table("tags").
-		Select("tags.name as name, COUNT(anime_tags.anime_id) as count").
-		Joins("JOIN anime_tags ON anime_tags.tag_id = tags.id").
-		Group("tags.name").
-		Scan(&results).Error
-	if err != nil {
+	return moveJoinRows(dao.db, movieTagsRef, sourceID, targetID)
+}
+
+// GetByCanonicalName 根据名称解析标签：依次按名称精确匹配、按同义词匹配、按规范化后的名称匹配，
+// 用于将大小写、全角半角、繁简体不同的写法解析到同一个标签
+func (dao *TagDAO) GetByCanonicalName(name string) (*models.Tag, error) {
+	tag, err := dao.GetByName(name)
+	if err == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+		return tag, err
+	}
+	canonicalName := common.CanonicalName(name)
+	var synonymTarget models.Tag
+	err = findBySynonym(dao.db, "tags", common.SynonymKindTag, canonicalName, &synonymTarget)
+	if err == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+		return &synonymTarget, err
+	}
+	var tags []models.Tag
+	if err := dao.db.Order("id").Find(&tags).Error; err != nil {
 		return nil, err
 	}
-
-	stats := make(map[string]int)
-	for _, result := range results {
-		stats[result.Name] = result.Count
+	for i := range tags {
+		if common.CanonicalName(tags[i].Name) == canonicalName {
+			return &tags[i], nil
+		}
 	}
-	return stats, nil
+	return nil, gorm.ErrRecordNotFound
+}
+
+// MoveSynonyms 将源标签的同义词移动到目标标签，并将源标签的名称登记为目标标签的同义词
+func (dao *TagDAO) MoveSynonyms(source, target *models.Tag) error {
+	return moveSynonyms(dao.db, common.SynonymKindTag, source.ID, target.ID, source.Name, target.Name)
+}
+
+// DeleteSynonyms 删除指向该标签的所有同义词
+func (dao *TagDAO) DeleteSynonyms(id uint) error {
+	return deleteSynonyms(dao.db, common.SynonymKindTag, id)
 }
@@ -0,0 +1,69 @@
+package dao
+
+import (
+	"kong-anime-go/internal/dao/models"
+)
+
+// CreateGroup 创建一个新的标签组
+func (dao *TagDAO) CreateGroup(group *models.TagGroup) error {
+	return dao.db.Create(group).Error
+}
+
+// GetGroupByID 根据ID获取标签组
+func (dao *TagDAO) GetGroupByID(id uint) (*models.TagGroup, error) {
+	var group models.TagGroup
+	err := dao.db.Preload("Tags").First(&group, id).Error
+	return &group, err
+}
+
+// GetGroupByName 根据名称获取标签组
+func (dao *TagDAO) GetGroupByName(name string) (*models.TagGroup, error) {
+	var group models.TagGroup
+	err := dao.db.Where("name = ?", name).First(&group).Error
+	return &group, err
+}
+
+// GetAllGroups 获取所有标签组及组内的标签
+func (dao *TagDAO) GetAllGroups() ([]models.TagGroup, error) {
+	var groups []models.TagGroup
+	err := dao.db.Preload("Tags").Order("id").Find(&groups).Error
+	return groups, err
+}
+
+// UpdateGroup 更新标签组
+func (dao *TagDAO) UpdateGroup(group *models.TagGroup) error {
+	return dao.db.Omit("Tags").Save(group).Error
+}
+
+// HardDeleteGroup 硬删除标签组，组内的标签移出该组
+func (dao *TagDAO) HardDeleteGroup(id uint) error {
+	if err := dao.db.Model(&models.Tag{}).Where("group_id = ?", id).Update("group_id", nil).Error; err != nil {
+		return err
+	}
+	return dao.db.Unscoped().Delete(&models.TagGroup{}, id).Error
+}
+
+// SetGroup 设置标签所属的标签组，groupID 为 nil 时移出标签组
+func (dao *TagDAO) SetGroup(id uint, groupID *uint) error {
+	return dao.db.Model(&models.Tag{}).Where("id = ?", id).Update("group_id", groupID).Error
+}
+
+// GroupedAnimeTag 动漫关联的属于某个标签组的标签
+type GroupedAnimeTag struct {
+	AnimeID uint
+	TagID   uint
+	TagName string
+	GroupID uint
+}
+
+// GetGroupedAnimeTags 获取所有动漫关联的、属于标签组的标签
+func (dao *TagDAO) GetGroupedAnimeTags() ([]GroupedAnimeTag, error) {
+	var rows []GroupedAnimeTag
+	err := dao.db.Table("anime_tags").
+		Select("anime_tags.anime_id AS anime_id, tags.id AS tag_id, tags.name AS tag_name, tags.group_id AS group_id").
+		Joins("JOIN tags ON tags.id = anime_tags.tag_id").
+		Where("tags.group_id IS NOT NULL AND tags.deleted_at IS NULL").
+		Order("anime_tags.anime_id, tags.id").
+		Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,113 @@
+package dao
+
+import (
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserDAO 定义用户和刷新令牌DAO
+type UserDAO struct {
+	db *gorm.DB
+}
+
+// NewUserDAO 创建用户DAO
+func NewUserDAO(db *gorm.DB) *UserDAO {
+	return &UserDAO{db: db}
+}
+
+// WithTx 返回使用事务 tx 的DAO
+func (dao *UserDAO) WithTx(tx *gorm.DB) *UserDAO {
+	return &UserDAO{db: tx}
+}
+
+// Transaction 在事务中执行 fn，fn 返回错误时回滚
+func (dao *UserDAO) Transaction(fn func(txDAO *UserDAO) error) error {
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		return fn(dao.WithTx(tx))
+	})
+}
+
+// Create 创建一个新的用户
+func (dao *UserDAO) Create(user *models.User) error {
+	return dao.db.Create(user).Error
+}
+
+// Update 更新用户
+func (dao *UserDAO) Update(user *models.User) error {
+	return dao.db.Save(user).Error
+}
+
+// GetByID 根据ID获取用户
+func (dao *UserDAO) GetByID(id uint) (*models.User, error) {
+	var user models.User
+	err := dao.db.First(&user, id).Error
+	return &user, err
+}
+
+// GetByUsername 根据登录名获取用户，不存在时返回 nil
+func (dao *UserDAO) GetByUsername(username string) (*models.User, error) {
+	var user models.User
+	err := dao.db.Where("username = ?", username).First(&user).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &user, err
+}
+
+// GetAll 获取所有用户
+func (dao *UserDAO) GetAll() ([]models.User, error) {
+	var users []models.User
+	err := dao.db.Order("id").Find(&users).Error
+	return users, err
+}
+
+// CountByRole 获取指定角色的用户数量
+func (dao *UserDAO) CountByRole(role common.Role) (int64, error) {
+	var count int64
+	err := dao.db.Model(&models.User{}).Where("role = ?", role).Count(&count).Error
+	return count, err
+}
+
+// Count 获取用户数量
+func (dao *UserDAO) Count() (int64, error) {
+	var count int64
+	err := dao.db.Model(&models.User{}).Count(&count).Error
+	return count, err
+}
+
+// AssignOrphanFollows 将不属于任何用户的追番（引入用户之前创建的追番）分配给用户，返回分配的数量
+func (dao *UserDAO) AssignOrphanFollows(userID uint) (int64, error) {
+	result := dao.db.Model(&models.Follow{}).Where("user_id = 0 OR user_id IS NULL").UpdateColumn("user_id", userID)
+	return result.RowsAffected, result.Error
+}
+
+// CreateRefreshToken 创建刷新令牌
+func (dao *UserDAO) CreateRefreshToken(token *models.RefreshToken) error {
+	return dao.db.Create(token).Error
+}
+
+// GetRefreshToken 根据令牌哈希获取未过期的刷新令牌
+func (dao *UserDAO) GetRefreshToken(tokenHash string, now time.Time) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := dao.db.Where("token_hash = ? AND expires_at > ?", tokenHash, now).First(&token).Error
+	return &token, err
+}
+
+// DeleteRefreshToken 硬删除用户的一个刷新令牌，返回是否删除了令牌
+func (dao *UserDAO) DeleteRefreshToken(userID uint, tokenHash string) (bool, error) {
+	result := dao.db.Unscoped().Where("user_id = ? AND token_hash = ?", userID, tokenHash).Delete(&models.RefreshToken{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteRefreshTokensByUserID 硬删除用户的所有刷新令牌
+func (dao *UserDAO) DeleteRefreshTokensByUserID(userID uint) error {
+	return dao.db.Unscoped().Where("user_id = ?", userID).Delete(&models.RefreshToken{}).Error
+}
+
+// DeleteExpiredRefreshTokens 硬删除已过期的刷新令牌
+func (dao *UserDAO) DeleteExpiredRefreshTokens(now time.Time) error {
+	return dao.db.Unscoped().Where("expires_at <= ?", now).Delete(&models.RefreshToken{}).Error
+}
@@ -0,0 +1,326 @@
+package i18n
+
+// messages 消息目录：原文 -> 语言 -> 翻译，原文所用的语言可以省略
+var messages = map[string]map[Lang]string{
+	// 通用
+	"record not found": {ZhCN: "记录不存在", Ja: "レコードが見つかりません"},
+	"Invalid ID":       {ZhCN: "无效的ID", Ja: "無効なIDです"},
+	"Invalid source_id": {
+		ZhCN: "无效的 source_id",
+		Ja:   "無効な source_id です",
+	},
+	"Invalid sorter": {ZhCN: "无效的排序方式", Ja: "無効な並び順です"},
+	"color must be in #RGB or #RRGGBB format": {
+		ZhCN: "颜色必须是 #RGB 或 #RRGGBB 格式",
+		Ja:   "色は #RGB または #RRGGBB 形式で指定してください",
+	},
+
+	// 动漫
+	"Anime not found": {ZhCN: "动漫不存在", Ja: "アニメが見つかりません"},
+	"source anime":    {ZhCN: "源动漫", Ja: "統合元のアニメ"},
+	"target anime":    {ZhCN: "目标动漫", Ja: "統合先のアニメ"},
+	"Name and Season are required": {
+		ZhCN: "名称和季度不能为空",
+		Ja:   "名前とシーズンは必須です",
+	},
+	"Invalid season format": {ZhCN: "无效的季度格式", Ja: "シーズンの形式が正しくありません"},
+	"Invalid airing status": {ZhCN: "无效的放送状态", Ja: "無効な放送状況です"},
+	"airing_status and airing_status_auto cannot be used together": {
+		ZhCN: "airing_status 和 airing_status_auto 不能同时使用",
+		Ja:   "airing_status と airing_status_auto は同時に指定できません",
+	},
+	"Invalid media type": {ZhCN: "无效的动漫类型", Ja: "無効な種別です"},
+	"episodes %d not allowed for media type %s (1-%d)": {
+		ZhCN: "集数 %d 不适用于类型 %s（应为 1-%d 集）",
+		Ja:   "話数 %d は種別 %s では指定できません（1〜%d 話）",
+	},
+	"episodes must be positive, omit it if unknown": {
+		ZhCN: "集数必须为正数，未知时不填",
+		Ja:   "話数は正の数で指定してください（不明な場合は省略してください）",
+	},
+	"Invalid external source": {ZhCN: "无效的外部数据库", Ja: "無効な外部データベースです"},
+	"Invalid source":          {ZhCN: "无效的外部数据库", Ja: "無効な外部データベースです"},
+	"external id is required": {ZhCN: "外部ID不能为空", Ja: "外部IDは必須です"},
+	"external id already linked to another anime": {
+		ZhCN: "该外部ID已关联到其他动漫",
+		Ja:   "この外部IDは別のアニメに紐付けられています",
+	},
+	"cannot delete anime with associated follow": {
+		ZhCN: "动漫有追番记录，不能删除",
+		Ja:   "追跡中のアニメは削除できません",
+	},
+	"Cannot merge anime into itself": {ZhCN: "不能将动漫合并到自身", Ja: "アニメを自分自身に統合することはできません"},
+	"Invalid follow strategy":        {ZhCN: "无效的追番合并方式", Ja: "無効な追跡の統合方法です"},
+	"invalid follow strategy %q":     {ZhCN: "无效的追番合并方式 %q", Ja: "無効な追跡の統合方法です: %q"},
+	"possible duplicate of existing anime (%s)": {
+		ZhCN: "可能与已有动漫重复（%s）",
+		Ja:   "既存のアニメと重複している可能性があります（%s）",
+	},
+	"Possible duplicate anime, use force=true to create anyway": {
+		ZhCN: "可能与已有动漫重复，使用 force=true 强制创建",
+		Ja:   "既存のアニメと重複している可能性があります。force=true で強制的に作成できます",
+	},
+	"unknown field %q":   {ZhCN: "未知的字段 %q", Ja: "不明な項目です: %q"},
+	"metadata not found": {ZhCN: "没有找到元数据", Ja: "メタデータが見つかりません"},
+	"metadata provider failed": {
+		ZhCN: "元数据服务请求失败",
+		Ja:   "メタデータの取得に失敗しました",
+	},
+	"no matching metadata found, specify external_id": {
+		ZhCN: "没有匹配的元数据，请指定 external_id",
+		Ja:   "一致するメタデータがありません。external_id を指定してください",
+	},
+	"no metadata provider for source": {
+		ZhCN: "该外部数据库没有元数据服务",
+		Ja:   "この外部データベースにはメタデータの提供元がありません",
+	},
+
+	// 分类和标签
+	"category not found": {ZhCN: "分类不存在", Ja: "カテゴリが見つかりません"},
+	"tag not found":      {ZhCN: "标签不存在", Ja: "タグが見つかりません"},
+	"parent category":    {ZhCN: "父分类", Ja: "親カテゴリ"},
+	"source category":    {ZhCN: "源分类", Ja: "統合元のカテゴリ"},
+	"target category":    {ZhCN: "目标分类", Ja: "統合先のカテゴリ"},
+	"parent tag":         {ZhCN: "父标签", Ja: "親タグ"},
+	"source tag":         {ZhCN: "源标签", Ja: "統合元のタグ"},
+	"target tag":         {ZhCN: "目标标签", Ja: "統合先のタグ"},
+	"tag group":          {ZhCN: "标签组", Ja: "タググループ"},
+	"cannot delete category with related items": {
+		ZhCN: "分类有关联的动漫或电影，不能删除",
+		Ja:   "アニメや映画に使われているカテゴリは削除できません",
+	},
+	"cannot delete tag with related items": {
+		ZhCN: "标签有关联的动漫或电影，不能删除",
+		Ja:   "アニメや映画に使われているタグは削除できません",
+	},
+	"cannot merge category into itself": {ZhCN: "不能将分类合并到自身", Ja: "カテゴリを自分自身に統合することはできません"},
+	"cannot merge tag into itself":      {ZhCN: "不能将标签合并到自身", Ja: "タグを自分自身に統合することはできません"},
+	"category %q already exists (id %d), merge into it instead": {
+		ZhCN: "分类 %q 已存在（ID %d），可以改为合并到该分类",
+		Ja:   "カテゴリ %q は既に存在します（ID %d）。代わりに統合してください",
+	},
+	"tag %q already exists (id %d), merge into it instead": {
+		ZhCN: "标签 %q 已存在（ID %d），可以改为合并到该标签",
+		Ja:   "タグ %q は既に存在します（ID %d）。代わりに統合してください",
+	},
+	"category cannot be placed under itself or its descendants": {
+		ZhCN: "分类不能放在自身或其子分类下",
+		Ja:   "カテゴリを自分自身やその子カテゴリの下に置くことはできません",
+	},
+	"tag cannot be placed under itself or its descendants": {
+		ZhCN: "标签不能放在自身或其子标签下",
+		Ja:   "タグを自分自身やその子タグの下に置くことはできません",
+	},
+	"category hierarchy cannot be deeper than %d levels": {
+		ZhCN: "分类层级不能超过 %d 层",
+		Ja:   "カテゴリの階層は %d 階層までです",
+	},
+	"tag hierarchy cannot be deeper than %d levels": {
+		ZhCN: "标签层级不能超过 %d 层",
+		Ja:   "タグの階層は %d 階層までです",
+	},
+	"order must list existing categories without duplicates": {
+		ZhCN: "排序列表中只能包含已有的分类且不能重复",
+		Ja:   "並び順には既存のカテゴリを重複なく指定してください",
+	},
+	"order must list existing tags without duplicates": {
+		ZhCN: "排序列表中只能包含已有的标签且不能重复",
+		Ja:   "並び順には既存のタグを重複なく指定してください",
+	},
+	"invalid cardinality, must be exactly_one, at_most_one or many": {
+		ZhCN: "无效的标签组规则，必须是 exactly_one、at_most_one 或 many",
+		Ja:   "無効なグループ規則です。exactly_one、at_most_one、many のいずれかを指定してください",
+	},
+	"tag group already exists": {ZhCN: "标签组已存在", Ja: "タググループは既に存在します"},
+	"tag group %q requires exactly one tag, got none": {
+		ZhCN: "标签组 %q 需要恰好一个标签，实际没有",
+		Ja:   "タググループ %q にはタグがちょうど 1 つ必要ですが、ありません",
+	},
+	"tag group %q requires exactly one tag, got %d (%s)": {
+		ZhCN: "标签组 %q 需要恰好一个标签，实际有 %d 个（%s）",
+		Ja:   "タググループ %q にはタグがちょうど 1 つ必要ですが、%d 個あります（%s）",
+	},
+	"tag group %q allows at most one tag, got %d (%s)": {
+		ZhCN: "标签组 %q 最多只能有一个标签，实际有 %d 个（%s）",
+		Ja:   "タググループ %q のタグは 1 つまでですが、%d 個あります（%s）",
+	},
+
+	// 同义词
+	"Synonym not found": {ZhCN: "同义词不存在", Ja: "同義語が見つかりません"},
+	"Name does not resolve to an existing entity": {
+		ZhCN: "名称没有对应的标签或分类",
+		Ja:   "名前に一致するタグやカテゴリがありません",
+	},
+	"invalid synonym kind, must be tag or category": {
+		ZhCN: "无效的同义词类型，必须是 tag 或 category",
+		Ja:   "無効な同義語の種類です。tag または category を指定してください",
+	},
+	"synonym name is required": {ZhCN: "同义词名称不能为空", Ja: "同義語の名前は必須です"},
+	"synonym already resolves to the target by normalization": {
+		ZhCN: "该名称归一化后已经对应目标，无需添加同义词",
+		Ja:   "この名前は正規化により既に対象に一致します",
+	},
+	"synonym already resolves to %q (id %d), merge them instead": {
+		ZhCN: "该名称已经对应 %q（ID %d），可以改为合并",
+		Ja:   "この名前は既に %q（ID %d）に一致します。代わりに統合してください",
+	},
+
+	// 制作公司和人物
+	"Studio not found":  {ZhCN: "制作公司不存在", Ja: "制作会社が見つかりません"},
+	"Person not found":  {ZhCN: "人物不存在", Ja: "人物が見つかりません"},
+	"movie not found":   {ZhCN: "电影不存在", Ja: "映画が見つかりません"},
+	"Invalid person ID": {ZhCN: "无效的人物ID", Ja: "無効な人物IDです"},
+	"Name is required":  {ZhCN: "名称不能为空", Ja: "名前は必須です"},
+	"Invalid role":      {ZhCN: "无效的担当", Ja: "無効な担当です"},
+	"cannot delete studio with related animes, merge it into another studio instead": {
+		ZhCN: "制作公司有关联的动漫，不能删除，可以改为合并到其他制作公司",
+		Ja:   "アニメに紐付けられた制作会社は削除できません。代わりに他の制作会社に統合してください",
+	},
+	"cannot merge studio into itself": {ZhCN: "不能将制作公司合并到自身", Ja: "制作会社を自分自身に統合することはできません"},
+	"source studio":                   {ZhCN: "源制作公司", Ja: "統合元の制作会社"},
+	"target studio":                   {ZhCN: "目标制作公司", Ja: "統合先の制作会社"},
+	"%q already refers to studio %q (id %d), merge into it instead": {
+		ZhCN: "%q 已经对应制作公司 %q（ID %d），可以改为合并到该制作公司",
+		Ja:   "%q は既に制作会社 %q（ID %d）に一致します。代わりに統合してください",
+	},
+	"cannot delete person with related credits": {
+		ZhCN: "人物有担当记录，不能删除",
+		Ja:   "担当が登録されている人物は削除できません",
+	},
+	"character name is only allowed for voice actors": {
+		ZhCN: "只有声优可以填写角色名",
+		Ja:   "役名は声優にのみ指定できます",
+	},
+	"exactly one of anime_id and movie_id is required": {
+		ZhCN: "anime_id 和 movie_id 必须且只能指定一个",
+		Ja:   "anime_id と movie_id のどちらか一方を指定してください",
+	},
+
+	// 追番
+	"follow not found": {ZhCN: "追番不存在", Ja: "追跡が見つかりません"},
+	"Follow already exists for this anime": {
+		ZhCN: "该动漫已经在追番中",
+		Ja:   "このアニメは既に追跡しています",
+	},
+	"cannot change AnimeID":   {ZhCN: "不能修改追番关联的动漫", Ja: "追跡対象のアニメは変更できません"},
+	"Invalid category":        {ZhCN: "无效的追番分类", Ja: "無効な追跡カテゴリです"},
+	"Invalid status":          {ZhCN: "无效的追番状态", Ja: "無効な視聴状況です"},
+	"Invalid score":           {ZhCN: "评分必须在 0 到 10 之间", Ja: "評価は 0 から 10 の間で指定してください"},
+	"unknown follow category": {ZhCN: "追番分类不存在", Ja: "追跡カテゴリが存在しません"},
+	"follow category name cannot be empty": {
+		ZhCN: "追番分类名称不能为空",
+		Ja:   "追跡カテゴリの名前は必須です",
+	},
+	"follow category name already exists": {
+		ZhCN: "已存在同名的追番分类",
+		Ja:   "同じ名前の追跡カテゴリが既に存在します",
+	},
+	"follow category is still used by follows": {
+		ZhCN: "仍有追番使用该分类，不能删除",
+		Ja:   "使用中の追跡カテゴリは削除できません",
+	},
+	"follow category is used by a recategorize rule": {
+		ZhCN: "自动归类规则使用该分类，不能删除",
+		Ja:   "自動分類ルールで使用中の追跡カテゴリは削除できません",
+	},
+	"order must list existing follow categories without duplicates": {
+		ZhCN: "排序列表中只能包含已有的追番分类且不能重复",
+		Ja:   "並び順には既存の追跡カテゴリを重複なく指定してください",
+	},
+	"invalid follow status transition": {ZhCN: "不允许的追番状态变更", Ja: "許可されていない視聴状況の変更です"},
+	"cannot change from %s to %s (allowed: %s)": {
+		ZhCN: "不能从 %s 变更为 %s（允许：%s）",
+		Ja:   "%s から %s には変更できません（変更可能：%s）",
+	},
+	"Run not found": {ZhCN: "归类记录不存在", Ja: "実行記録が見つかりません"},
+
+	// 导入导出
+	"Import job not found":         {ZhCN: "导入任务不存在", Ja: "インポートジョブが見つかりません"},
+	"invalid export format":        {ZhCN: "无效的导出格式", Ja: "無効なエクスポート形式です"},
+	"invalid MAL export":           {ZhCN: "无效的 MyAnimeList 导出文件", Ja: "無効な MyAnimeList エクスポートファイルです"},
+	"MAL export contains no anime": {ZhCN: "MyAnimeList 导出文件中没有动漫", Ja: "MyAnimeList エクスポートファイルにアニメがありません"},
+	"invalid header":               {ZhCN: "无效的表头", Ja: "無効なヘッダーです"},
+	"missing name column":          {ZhCN: "缺少名称列", Ja: "名前の列がありません"},
+	"missing season column":        {ZhCN: "缺少季度列", Ja: "シーズンの列がありません"},
+	"no rows to import":            {ZhCN: "没有可导入的行", Ja: "インポートする行がありません"},
+
+	// 备份
+	"database is not empty, restore requires an empty database": {
+		ZhCN: "数据库不为空，只能恢复到空数据库",
+		Ja:   "データベースが空ではありません。復元は空のデータベースにのみ行えます",
+	},
+	"invalid backup archive":            {ZhCN: "无效的备份文件", Ja: "無効なバックアップファイルです"},
+	"unsupported backup schema version": {ZhCN: "不支持的备份结构版本", Ja: "サポートされていないバックアップのバージョンです"},
+	"backup dir is required":            {ZhCN: "备份目录不能为空", Ja: "バックアップ先のディレクトリは必須です"},
+	"backup retention must not be negative": {
+		ZhCN: "备份保留数量不能为负数",
+		Ja:   "バックアップの保持数に負の値は指定できません",
+	},
+
+	// 登录
+	"Authentication required":       {ZhCN: "需要登录", Ja: "ログインが必要です"},
+	"invalid username or password":  {ZhCN: "用户名或密码错误", Ja: "ユーザー名またはパスワードが正しくありません"},
+	"invalid or expired token":      {ZhCN: "令牌无效或已过期", Ja: "トークンが無効か、有効期限が切れています"},
+	"username must not be empty":    {ZhCN: "用户名不能为空", Ja: "ユーザー名は必須です"},
+	"username already exists":       {ZhCN: "用户名已存在", Ja: "ユーザー名は既に使われています"},
+	"current password is incorrect": {ZhCN: "当前密码错误", Ja: "現在のパスワードが正しくありません"},
+	"Insufficient permissions":      {ZhCN: "权限不足", Ja: "権限がありません"},
+	"role must be viewer, editor or admin": {
+		ZhCN: "角色必须是 viewer、editor 或 admin",
+		Ja:   "ロールは viewer、editor、admin のいずれかにしてください",
+	},
+	"cannot demote the last admin": {ZhCN: "不能取消最后一个管理员的管理员角色", Ja: "最後の管理者のロールは変更できません"},
+	"password must be at least %d characters": {
+		ZhCN: "密码至少需要 %d 个字符",
+		Ja:   "パスワードは %d 文字以上にしてください",
+	},
+}
+
+// builtinFollowCategories 内置追番分类的名称和描述，原文为中文，用户修改后的名称没有翻译
+var builtinFollowCategories = map[string]map[Lang]string{
+	"旧时代的残党":            {En: "Relics of the Old Era", Ja: "旧時代の残党"},
+	"经典老番或长篇番":          {En: "Classics and long-running series", Ja: "懐かしの名作や長編シリーズ"},
+	"我们仍未知道那天所看见的番剧的名字": {En: "The Anime We Heard Good Things About", Ja: "あの日見た番組の名前を僕達はまだ知らない"},
+	"听说高质量的番":           {En: "Shows said to be high quality", Ja: "評判の高い作品"},
+	"新番妙妙屋":             {En: "New Season Picks", Ja: "新番組コーナー"},
+	"看新番导视比较感兴趣的番":      {En: "New shows that looked interesting in the season preview", Ja: "新番組ガイドで気になった作品"},
+	"厕纸":                {En: "Guilty Pleasures", Ja: "暇つぶし枠"},
+	"专门用来找乐子杀时间的番":      {En: "Shows watched just for fun and to kill time", Ja: "気楽に楽しむための作品"},
+	"神！":                {En: "Masterpieces!", Ja: "神！"},
+	"无需多言":              {En: "Needs no explanation", Ja: "言うまでもない"},
+}
+
+// labels 枚举值的显示名称，键为 "枚举类型.值"
+var labels = map[string]map[Lang]string{
+	"media_type.tv":      {ZhCN: "TV", En: "TV", Ja: "TV"},
+	"media_type.ova":     {ZhCN: "OVA", En: "OVA", Ja: "OVA"},
+	"media_type.ona":     {ZhCN: "ONA", En: "ONA", Ja: "ONA"},
+	"media_type.special": {ZhCN: "特别篇", En: "Special", Ja: "特別編"},
+	"media_type.web":     {ZhCN: "网络动画", En: "Web", Ja: "Web アニメ"},
+
+	"airing_status.upcoming":  {ZhCN: "未开播", En: "Upcoming", Ja: "放送前"},
+	"airing_status.airing":    {ZhCN: "连载中", En: "Airing", Ja: "放送中"},
+	"airing_status.finished":  {ZhCN: "已完结", En: "Finished", Ja: "放送終了"},
+	"airing_status.hiatus":    {ZhCN: "停播中", En: "On hiatus", Ja: "休止中"},
+	"airing_status.cancelled": {ZhCN: "已取消", En: "Cancelled", Ja: "中止"},
+
+	"follow_status.want_to_watch": {ZhCN: "想看", En: "Plan to watch", Ja: "見たい"},
+	"follow_status.watching":      {ZhCN: "在看", En: "Watching", Ja: "視聴中"},
+	"follow_status.watched":       {ZhCN: "看过", En: "Completed", Ja: "視聴済み"},
+	"follow_status.on_hold":       {ZhCN: "搁置", En: "On hold", Ja: "一時停止"},
+	"follow_status.dropped":       {ZhCN: "弃番", En: "Dropped", Ja: "視聴中止"},
+	"follow_status.rewatching":    {ZhCN: "重温", En: "Rewatching", Ja: "再視聴中"},
+
+	"credit_role.director":         {ZhCN: "导演", En: "Director", Ja: "監督"},
+	"credit_role.script":           {ZhCN: "脚本", En: "Script", Ja: "脚本"},
+	"credit_role.music":            {ZhCN: "音乐", En: "Music", Ja: "音楽"},
+	"credit_role.character_design": {ZhCN: "人物设计", En: "Character design", Ja: "キャラクターデザイン"},
+	"credit_role.voice_actor":      {ZhCN: "声优", En: "Voice actor", Ja: "声優"},
+
+	"tag_cardinality.exactly_one": {ZhCN: "恰好一个", En: "Exactly one", Ja: "ちょうど 1 つ"},
+	"tag_cardinality.at_most_one": {ZhCN: "最多一个", En: "At most one", Ja: "1 つまで"},
+	"tag_cardinality.many":        {ZhCN: "不限", En: "Any number", Ja: "制限なし"},
+	"role.viewer":                 {ZhCN: "浏览者", En: "Viewer", Ja: "閲覧者"},
+	"role.editor":                 {ZhCN: "编辑者", En: "Editor", Ja: "編集者"},
+	"role.admin":                  {ZhCN: "管理员", En: "Administrator", Ja: "管理者"},
+}
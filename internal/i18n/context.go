@@ -0,0 +1,29 @@
+package i18n
+
+import "github.com/gin-gonic/gin"
+
+// contextKey 请求语言在 gin.Context 中的键
+const contextKey = "i18n.lang"
+
+// SetLang 设置请求使用的语言
+func SetLang(c *gin.Context, lang Lang) {
+	c.Set(contextKey, lang)
+}
+
+// FromContext 返回请求使用的语言，未设置时返回默认语言
+func FromContext(c *gin.Context) Lang {
+	if lang, ok := c.Get(contextKey); ok {
+		return lang.(Lang)
+	}
+	return Default
+}
+
+// Message 将 key 翻译为请求使用的语言
+func Message(c *gin.Context, key string) string {
+	return Translate(FromContext(c), key)
+}
+
+// ErrorMessage 将 err 翻译为请求使用的语言
+func ErrorMessage(c *gin.Context, err error) string {
+	return LocalizeError(FromContext(c), err)
+}
@@ -0,0 +1,158 @@
+// Package i18n 提供按语言翻译接口返回的错误信息、枚举名称和季度名称的消息目录
+//
+// 消息以原文（错误信息为英文，内置的追番分类为中文）作为键，查找时不区分大小写，目录中没有对应翻译时返回原文
+package i18n
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Lang 支持的语言
+type Lang string
+
+// 支持的语言
+const (
+	ZhCN Lang = "zh-CN"
+	En   Lang = "en"
+	Ja   Lang = "ja"
+)
+
+// Default 请求没有指定语言或指定的语言不受支持时使用的语言
+const Default = En
+
+// supported 与 matcher 中的语言顺序一致
+var supported = []Lang{ZhCN, En, Ja}
+
+var matcher = language.NewMatcher([]language.Tag{language.SimplifiedChinese, language.English, language.Japanese})
+
+// Supported 返回所有支持的语言
+func Supported() []Lang {
+	return append([]Lang{}, supported...)
+}
+
+// Negotiate 根据 ?lang= 参数和 Accept-Language 请求头选择语言，参数优先
+func Negotiate(query, acceptLanguage string) Lang {
+	if query != "" {
+		if tag, err := language.Parse(query); err == nil {
+			if _, idx, conf := matcher.Match(tag); conf != language.No {
+				return supported[idx]
+			}
+		}
+	}
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+	if _, idx, conf := matcher.Match(tags...); conf != language.No {
+		return supported[idx]
+	}
+	return Default
+}
+
+// Translate 返回 key 在 lang 中的翻译，没有翻译时返回 key 本身
+func Translate(lang Lang, key string) string {
+	if s, ok := lookup(lang, key); ok {
+		return s
+	}
+	return key
+}
+
+// Sprintf 使用 format 在 lang 中的翻译格式化消息，翻译中的参数顺序与原文一致
+func Sprintf(lang Lang, format string, args ...any) string {
+	return fmt.Sprintf(Translate(lang, format), args...)
+}
+
+// foldedMessages 以小写原文为键的消息目录，错误信息的原文只有大小写不同时共用同一条翻译
+var foldedMessages = foldKeys(messages)
+
+func foldKeys(m map[string]map[Lang]string) map[string]map[Lang]string {
+	folded := make(map[string]map[Lang]string, len(m))
+	for key, translations := range m {
+		folded[strings.ToLower(key)] = translations
+	}
+	return folded
+}
+
+func lookup(lang Lang, key string) (string, bool) {
+	translations, ok := messages[key]
+	if !ok {
+		translations = foldedMessages[strings.ToLower(key)]
+	}
+	s, ok := translations[lang]
+	return s, ok && s != ""
+}
+
+// Localizable 可以按语言生成错误信息的错误，用于带有参数的自定义错误类型
+type Localizable interface {
+	Localize(lang Lang) string
+}
+
+// Error 可翻译的格式化错误，Error() 返回英文原文
+type Error struct {
+	format string
+	args   []any
+}
+
+// Errorf 创建可翻译的格式化错误，format 作为消息目录的键
+func Errorf(format string, args ...any) *Error {
+	return &Error{format: format, args: args}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf(e.format, e.args...)
+}
+
+// Localize 返回 lang 中的错误信息
+func (e *Error) Localize(lang Lang) string {
+	return Sprintf(lang, e.format, e.args...)
+}
+
+// LocalizeError 返回 err 在 lang 中的错误信息
+// 包装的错误（fmt.Errorf 的 %w 和 errors.Join）逐层翻译：被包装的错误原位替换为其翻译，包装时附加的文字有翻译时一并替换
+func LocalizeError(lang Lang, err error) string {
+	if l, ok := err.(Localizable); ok {
+		return l.Localize(lang)
+	}
+	msg := err.Error()
+	if s, ok := lookup(lang, msg); ok {
+		return s
+	}
+	var children []error
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		if child := e.Unwrap(); child != nil {
+			children = []error{child}
+		}
+	case interface{ Unwrap() []error }:
+		children = e.Unwrap()
+	}
+	var b strings.Builder
+	pos := 0
+	for _, child := range children {
+		childMsg := child.Error()
+		i := strings.Index(msg[pos:], childMsg)
+		if i < 0 {
+			continue
+		}
+		b.WriteString(localizeSegment(lang, msg[pos:pos+i]))
+		b.WriteString(LocalizeError(lang, child))
+		pos += i + len(childMsg)
+	}
+	b.WriteString(localizeSegment(lang, msg[pos:]))
+	return b.String()
+}
+
+// localizeSegment 翻译包装错误时附加的文字，如 "source tag: " 中的 "source tag"
+func localizeSegment(lang Lang, segment string) string {
+	core := strings.Trim(segment, ": \n")
+	if core == "" {
+		return segment
+	}
+	if s, ok := lookup(lang, core); ok {
+		return strings.Replace(segment, core, s, 1)
+	}
+	return segment
+}
@@ -0,0 +1,51 @@
+package i18n
+
+import (
+	"fmt"
+	"time"
+)
+
+// Label 返回枚举值在 lang 中的显示名称，kind 为枚举类型（如 follow_status），没有翻译时返回 value
+func Label(lang Lang, kind, value string) string {
+	if s := labels[kind+"."+value][lang]; s != "" {
+		return s
+	}
+	return value
+}
+
+// FollowCategoryText 翻译内置追番分类的名称或描述，用户自定义的文字原样返回
+func FollowCategoryText(lang Lang, text string) string {
+	if s := builtinFollowCategories[text][lang]; s != "" {
+		return s
+	}
+	return text
+}
+
+// quarters 1、4、7、10 月开播的季度名称
+var quarters = map[time.Month]map[Lang]string{
+	time.January: {En: "Winter", Ja: "冬"},
+	time.April:   {En: "Spring", Ja: "春"},
+	time.July:    {En: "Summer", Ja: "夏"},
+	time.October: {En: "Fall", Ja: "秋"},
+}
+
+// SeasonName 返回 2024-01 格式的季度在 lang 中的显示名称，如 2024年1月、Winter 2024、2024年冬
+// 不是季度开始的月份按月显示，格式不正确时原样返回
+func SeasonName(lang Lang, season string) string {
+	t, err := time.Parse("2006-01", season)
+	if err != nil {
+		return season
+	}
+	year, month := t.Year(), t.Month()
+	quarter := quarters[month][lang]
+	switch {
+	case lang == En && quarter != "":
+		return fmt.Sprintf("%s %d", quarter, year)
+	case lang == En:
+		return fmt.Sprintf("%s %d", month, year)
+	case lang == Ja && quarter != "":
+		return fmt.Sprintf("%d年%s", year, quarter)
+	default:
+		return fmt.Sprintf("%d年%d月", year, month)
+	}
+}
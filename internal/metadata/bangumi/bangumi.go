@@ -0,0 +1,214 @@
+package bangumi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/metadata"
+)
+
+// subjectTypeAnime Bangumi 中动画条目的类型
+const subjectTypeAnime = 2
+
+// Subject Bangumi API (v0) 中的条目，只包含用到的字段
+type Subject struct {
+	ID            int          `json:"id"`
+	Type          int          `json:"type"`
+	Name          string       `json:"name"`    // 原名
+	NameCN        string       `json:"name_cn"` // 中文名
+	Date          string       `json:"date"`    // 放送开始日期，如 2023-09-29
+	Platform      string       `json:"platform"`
+	Eps           int          `json:"eps"`
+	TotalEpisodes int          `json:"total_episodes"`
+	Images        Images       `json:"images"`
+	Infobox       []InfoboxRow `json:"infobox"`
+}
+
+// Images 条目图片
+type Images struct {
+	Large  string `json:"large"`
+	Common string `json:"common"`
+}
+
+// InfoboxRow 条目信息框中的一行，值可能是字符串，也可能是 [{"v": "..."}] 列表
+type InfoboxRow struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Values 返回信息框中这一行的所有值
+func (r InfoboxRow) Values() []string {
+	var s string
+	if err := json.Unmarshal(r.Value, &s); err == nil {
+		return common.SplitNames(s)
+	}
+	var list []struct {
+		V string `json:"v"`
+	}
+	if err := json.Unmarshal(r.Value, &list); err != nil {
+		return nil
+	}
+	values := make([]string, 0, len(list))
+	for _, item := range list {
+		if v := strings.TrimSpace(item.V); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// platformMediaTypes Bangumi 放送平台到动漫类型的映射，剧场版等不在此列
+var platformMediaTypes = map[string]common.MediaType{
+	"tv":  common.MediaTypeTV,
+	"ova": common.MediaTypeOVA,
+	"ona": common.MediaTypeONA,
+	"web": common.MediaTypeWeb,
+	"sp":  common.MediaTypeSpecial,
+}
+
+// Client Bangumi API 客户端，实现 metadata.MetadataProvider
+type Client struct {
+	baseURL    string
+	userAgent  string
+	httpClient *http.Client
+}
+
+// NewClient 创建 Bangumi API 客户端，baseURL 可以指向官方 API 或本地的替身服务器
+func NewClient(baseURL, userAgent string, timeout time.Duration) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		userAgent:  userAgent,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Source 数据源
+func (c *Client) Source() common.ExternalSource {
+	return common.ExternalSourceBangumi
+}
+
+// Search 按关键字搜索动画条目
+func (c *Client) Search(ctx context.Context, keyword string) ([]metadata.Metadata, error) {
+	body, err := json.Marshal(map[string]any{
+		"keyword": keyword,
+		"filter":  map[string]any{"type": []int{subjectTypeAnime}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Data []Subject `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/v0/search/subjects?limit=10", body, &result); err != nil {
+		return nil, err
+	}
+	candidates := make([]metadata.Metadata, 0, len(result.Data))
+	for _, subject := range result.Data {
+		candidates = append(candidates, *subject.toMetadata())
+	}
+	return candidates, nil
+}
+
+// Get 获取条目详情
+func (c *Client) Get(ctx context.Context, externalID string) (*metadata.Metadata, error) {
+	if _, err := strconv.Atoi(externalID); err != nil {
+		return nil, fmt.Errorf("invalid bangumi subject id %q", externalID)
+	}
+	var subject Subject
+	if err := c.do(ctx, http.MethodGet, "/v0/subjects/"+url.PathEscape(externalID), nil, &subject); err != nil {
+		return nil, err
+	}
+	return subject.toMetadata(), nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body []byte, v any) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("bangumi: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return metadata.ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("bangumi: %s %s: %s %s", method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("bangumi: invalid response: %w", err)
+	}
+	return nil
+}
+
+// toMetadata 转换为通用的元数据：优先使用中文名，原名和信息框中的别名作为别名
+func (s *Subject) toMetadata() *metadata.Metadata {
+	m := &metadata.Metadata{
+		Source:     common.ExternalSourceBangumi,
+		ExternalID: strconv.Itoa(s.ID),
+		Name:       s.NameCN,
+		Episodes:   s.TotalEpisodes,
+		Image:      s.Images.Large,
+		MediaType:  common.MediaTypeUnknown,
+	}
+	if m.Name == "" {
+		m.Name = s.Name
+	} else if s.Name != "" && s.Name != m.Name {
+		m.Aliases = append(m.Aliases, s.Name)
+	}
+	if m.Episodes == 0 {
+		m.Episodes = s.Eps
+	}
+	if m.Image == "" {
+		m.Image = s.Images.Common
+	}
+	if mediaType, ok := platformMediaTypes[strings.ToLower(s.Platform)]; ok {
+		m.MediaType = mediaType
+	}
+	if date, err := time.Parse(time.DateOnly, s.Date); err == nil {
+		m.Season = seasonOf(date)
+	}
+
+	var studios []string
+	for _, row := range s.Infobox {
+		switch row.Key {
+		case "别名", "中文名":
+			for _, alias := range row.Values() {
+				if alias != m.Name && !slices.Contains(m.Aliases, alias) {
+					m.Aliases = append(m.Aliases, alias)
+				}
+			}
+		case "动画制作", "制作":
+			studios = append(studios, row.Values()...)
+		}
+	}
+	m.Production = strings.Join(studios, "、")
+	return m
+}
+
+// seasonOf 返回放送开始日期所在的季度，如 2023-09-29 对应 2023-07
+func seasonOf(date time.Time) string {
+	month := (int(date.Month())-1)/3*3 + 1
+	return fmt.Sprintf("%d-%02d", date.Year(), month)
+}
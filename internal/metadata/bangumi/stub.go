@@ -0,0 +1,83 @@
+package bangumi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"kong-anime-go/internal/common"
+)
+
+// StubServer 本地替身服务器，用固定的条目模拟 Bangumi API 中用到的接口，用于测试和离线使用
+type StubServer struct {
+	subjects []Subject
+}
+
+// NewStubServer 创建替身服务器
+func NewStubServer(subjects []Subject) *StubServer {
+	return &StubServer{subjects: subjects}
+}
+
+// ServeHTTP 实现 GET /v0/subjects/{id} 和 POST /v0/search/subjects
+func (s *StubServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v0/subjects/"):
+		id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/v0/subjects/"))
+		if err != nil {
+			writeStubJSON(w, http.StatusBadRequest, map[string]string{"title": "Bad Request"})
+			return
+		}
+		for _, subject := range s.subjects {
+			if subject.ID == id {
+				writeStubJSON(w, http.StatusOK, subject)
+				return
+			}
+		}
+		writeStubJSON(w, http.StatusNotFound, map[string]string{"title": "Not Found"})
+	case r.Method == http.MethodPost && r.URL.Path == "/v0/search/subjects":
+		var req struct {
+			Keyword string `json:"keyword"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeStubJSON(w, http.StatusBadRequest, map[string]string{"title": "Bad Request"})
+			return
+		}
+		data := []Subject{}
+		for _, subject := range s.subjects {
+			if s.matches(subject, req.Keyword) {
+				data = append(data, subject)
+			}
+		}
+		writeStubJSON(w, http.StatusOK, map[string]any{"data": data, "total": len(data)})
+	default:
+		writeStubJSON(w, http.StatusNotFound, map[string]string{"title": "Not Found"})
+	}
+}
+
+// matches 关键字与原名、中文名或别名相似时视为匹配
+func (s *StubServer) matches(subject Subject, keyword string) bool {
+	keyword = common.NormalizeName(keyword)
+	if keyword == "" {
+		return false
+	}
+	names := []string{subject.Name, subject.NameCN}
+	for _, row := range subject.Infobox {
+		if row.Key == "别名" || row.Key == "中文名" {
+			names = append(names, row.Values()...)
+		}
+	}
+	for _, name := range names {
+		normalized := common.NormalizeName(name)
+		if normalized != "" && (strings.Contains(normalized, keyword) || strings.Contains(keyword, normalized)) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeStubJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,34 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+
+	"kong-anime-go/internal/common"
+)
+
+// ErrNotFound 数据源中找不到对应的条目
+var ErrNotFound = errors.New("metadata not found")
+
+// Metadata 外部数据源提供的动漫元数据，未知的字段为零值
+type Metadata struct {
+	Source     common.ExternalSource `json:"source"`
+	ExternalID string                `json:"external_id"`
+	Name       string                `json:"name"`
+	Aliases    []string              `json:"aliases"`
+	Production string                `json:"production"`
+	Season     string                `json:"season"` // 格式为 2006-01
+	Episodes   int                   `json:"episodes"`
+	Image      string                `json:"image"`
+	MediaType  common.MediaType      `json:"media_type"` // 无法对应时为 MediaTypeUnknown
+}
+
+// MetadataProvider 动漫元数据提供者
+type MetadataProvider interface {
+	// Source 数据源，同时决定获取到的条目ID记录到哪个外部数据库
+	Source() common.ExternalSource
+	// Search 按关键字搜索条目
+	Search(ctx context.Context, keyword string) ([]Metadata, error)
+	// Get 获取条目详情，找不到时返回 ErrNotFound
+	Get(ctx context.Context, externalID string) (*Metadata, error)
+}
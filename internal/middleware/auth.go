@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao/models"
+	"kong-anime-go/internal/i18n"
+	"kong-anime-go/internal/services/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 登录用户在 gin.Context 中的键
+const userKey = "auth.user"
+
+// Permissions 路由权限表，键为 "方法 完整路径"（如 "DELETE /api/v1/animes/:id"），值为需要的最低角色
+// 未列出的 GET 路由为公开读取，未列出的其他路由需要管理员
+type Permissions map[string]common.Role
+
+// Required 返回路由需要的最低角色，anonymousRead 为 true 时未列出的 GET 路由不需要登录
+func (p Permissions) Required(method, path string, anonymousRead bool) common.Role {
+	if role, ok := p[method+" "+path]; ok {
+		return role
+	}
+	if method == http.MethodGet {
+		if anonymousRead {
+			return common.RoleAnonymous
+		}
+		return common.RoleViewer
+	}
+	return common.RoleAdmin
+}
+
+// Validate 检查权限表中的每一项都对应已注册的路由，避免路径写错导致权限悄悄回落到默认值
+func (p Permissions) Validate(routes gin.RoutesInfo) error {
+	registered := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		registered[route.Method+" "+route.Path] = true
+	}
+	var errs []error
+	for key, role := range p {
+		if !registered[key] {
+			errs = append(errs, fmt.Errorf("permission for unknown route %q", key))
+		}
+		if role != common.RoleAnonymous && !role.IsValid() {
+			errs = append(errs, fmt.Errorf("invalid role %q for route %q", role, key))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// AuthMiddleware 按路由权限表校验 Authorization: Bearer <access token> 请求头
+// 未登录或令牌无效时返回 401，角色权限不足时返回 403
+func AuthMiddleware(authSrv *auth.Service, permissions Permissions, anonymousRead bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.FullPath() == "" {
+			// 没有匹配的路由，交给 gin 返回 404
+			c.Next()
+			return
+		}
+		required := permissions.Required(c.Request.Method, c.FullPath(), anonymousRead)
+
+		header := c.GetHeader("Authorization")
+		if header == "" {
+			if required == common.RoleAnonymous {
+				c.Next()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": i18n.Message(c, "Authentication required")})
+			return
+		}
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": i18n.ErrorMessage(c, auth.ErrInvalidToken)})
+			return
+		}
+		user, err := authSrv.Authenticate(strings.TrimSpace(token))
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, auth.ErrInvalidToken) {
+				status = http.StatusUnauthorized
+			}
+			c.AbortWithStatusJSON(status, gin.H{"error": i18n.ErrorMessage(c, err)})
+			return
+		}
+		if !user.Role.Allows(required) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": i18n.Message(c, "Insufficient permissions"), "required_role": required})
+			return
+		}
+		c.Set(userKey, user)
+		c.Next()
+	}
+}
+
+// CurrentUser 返回登录用户，只能在需要登录的路由中使用
+func CurrentUser(c *gin.Context) *models.User {
+	return c.MustGet(userKey).(*models.User)
+}
+
+// CurrentUserID 返回登录用户的ID
+func CurrentUserID(c *gin.Context) uint {
+	return CurrentUser(c).ID
+}
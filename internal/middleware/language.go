@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"kong-anime-go/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LanguageMiddleware 根据 ?lang= 参数或 Accept-Language 请求头选择响应使用的语言
+func LanguageMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := i18n.Negotiate(c.Query("lang"), c.GetHeader("Accept-Language"))
+		i18n.SetLang(c, lang)
+		c.Writer.Header().Set("Content-Language", string(lang))
+		c.Next()
+	}
+}
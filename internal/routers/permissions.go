@@ -0,0 +1,102 @@
+package routers
+
+import (
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/middleware"
+)
+
+// permissions 路由权限表：编辑动漫目录需要 editor，删除、合并等破坏性操作和管理接口需要 admin，
+// 追番等个人数据需要登录；未列出的 GET 路由按 auth.anonymous_read 决定是否允许匿名读取，未列出的其他路由需要 admin
+var permissions = middleware.Permissions{
+	// Auth
+	"POST /api/v1/auth/login":   common.RoleAnonymous,
+	"POST /api/v1/auth/refresh": common.RoleAnonymous,
+	"POST /api/v1/auth/logout":  common.RoleViewer,
+	"GET /api/v1/auth/me":       common.RoleViewer,
+	"PUT /api/v1/auth/password": common.RoleViewer,
+
+	// Anime
+	"POST /api/v1/animes":                            common.RoleEditor,
+	"PUT /api/v1/animes/:id":                         common.RoleEditor,
+	"PATCH /api/v1/animes/:id/categories":            common.RoleEditor,
+	"PATCH /api/v1/animes/:id/tags":                  common.RoleEditor,
+	"POST /api/v1/animes/:id/external-ids":           common.RoleEditor,
+	"DELETE /api/v1/animes/:id/external-ids/:source": common.RoleEditor,
+	"POST /api/v1/animes/:id/enrich":                 common.RoleEditor,
+	"PUT /api/v1/animes/:id/locks":                   common.RoleEditor,
+	"DELETE /api/v1/animes/:id":                      common.RoleAdmin,
+	"POST /api/v1/animes/:id/merge":                  common.RoleAdmin,
+
+	// Category
+	"POST /api/v1/categories":           common.RoleEditor,
+	"PUT /api/v1/categories/:id":        common.RoleEditor,
+	"PUT /api/v1/categories/:id/parent": common.RoleEditor,
+	"PUT /api/v1/categories/order":      common.RoleEditor,
+	"DELETE /api/v1/categories/:id":     common.RoleAdmin,
+	"POST /api/v1/categories/:id/merge": common.RoleAdmin,
+
+	// Tag
+	"POST /api/v1/tags":           common.RoleEditor,
+	"PUT /api/v1/tags/:id":        common.RoleEditor,
+	"PUT /api/v1/tags/:id/parent": common.RoleEditor,
+	"PUT /api/v1/tags/order":      common.RoleEditor,
+	"PUT /api/v1/tags/:id/group":  common.RoleEditor,
+	"DELETE /api/v1/tags/:id":     common.RoleAdmin,
+	"POST /api/v1/tags/:id/merge": common.RoleAdmin,
+
+	// Tag group
+	"POST /api/v1/tag-groups":       common.RoleEditor,
+	"PUT /api/v1/tag-groups/:id":    common.RoleEditor,
+	"DELETE /api/v1/tag-groups/:id": common.RoleAdmin,
+
+	// Studio
+	"POST /api/v1/studios":           common.RoleEditor,
+	"PUT /api/v1/studios/:id":        common.RoleEditor,
+	"DELETE /api/v1/studios/:id":     common.RoleAdmin,
+	"POST /api/v1/studios/:id/merge": common.RoleAdmin,
+
+	// Person
+	"POST /api/v1/people":        common.RoleEditor,
+	"PUT /api/v1/people/:id":     common.RoleEditor,
+	"DELETE /api/v1/people/:id":  common.RoleAdmin,
+	"POST /api/v1/credits":       common.RoleEditor,
+	"DELETE /api/v1/credits/:id": common.RoleEditor,
+	"GET /api/v1/people/stats":   common.RoleViewer,
+
+	// Follow（追番按用户区分，分类所有用户共享）
+	"POST /api/v1/follows":                     common.RoleViewer,
+	"DELETE /api/v1/follows/:id":               common.RoleViewer,
+	"PUT /api/v1/follows/:id":                  common.RoleViewer,
+	"GET /api/v1/follows/:id":                  common.RoleViewer,
+	"GET /api/v1/follows":                      common.RoleViewer,
+	"PATCH /api/v1/follows/:id/status":         common.RoleViewer,
+	"POST /api/v1/follows/categories":          common.RoleEditor,
+	"PUT /api/v1/follows/categories/:value":    common.RoleEditor,
+	"PUT /api/v1/follows/categories/order":     common.RoleEditor,
+	"DELETE /api/v1/follows/categories/:value": common.RoleAdmin,
+
+	// Export / Import
+	"GET /api/v1/export/follows": common.RoleViewer,
+	"POST /api/v1/imports/mal":   common.RoleViewer, // viewer 只能匹配已有的动漫，新建动漫需要 editor
+	"POST /api/v1/imports/csv":   common.RoleEditor,
+	"GET /api/v1/imports":        common.RoleViewer, // 只返回当前用户发起的导入任务
+	"GET /api/v1/imports/:id":    common.RoleViewer,
+
+	// Recategorize
+	"GET /api/v1/follows/recategorize/preview":  common.RoleViewer,
+	"POST /api/v1/follows/recategorize":         common.RoleViewer,
+	"GET /api/v1/follows/recategorize/runs":     common.RoleViewer,
+	"GET /api/v1/follows/recategorize/runs/:id": common.RoleViewer,
+
+	// Admin
+	"GET /api/v1/admin/backup":           common.RoleAdmin,
+	"POST /api/v1/admin/restore":         common.RoleAdmin,
+	"GET /api/v1/admin/backups":          common.RoleAdmin,
+	"GET /api/v1/admin/synonyms":         common.RoleAdmin,
+	"POST /api/v1/admin/synonyms":        common.RoleAdmin,
+	"DELETE /api/v1/admin/synonyms/:id":  common.RoleAdmin,
+	"GET /api/v1/admin/synonyms/resolve": common.RoleAdmin,
+	"GET /api/v1/admin/users":            common.RoleAdmin,
+	"POST /api/v1/admin/users":           common.RoleAdmin,
+	"PUT /api/v1/admin/users/:id/role":   common.RoleAdmin,
+}
@@ -1,16 +1,40 @@
 package routers
 
 import (
+	"context"
+	"log"
+
 	"kong-anime-go/internal/api/anime"
+	"kong-anime-go/internal/api/auth"
+	"kong-anime-go/internal/api/backup"
 	"kong-anime-go/internal/api/category"
+	"kong-anime-go/internal/api/enrich"
+	"kong-anime-go/internal/api/exporter"
 	"kong-anime-go/internal/api/follow" // 添加追番API的导入
+	"kong-anime-go/internal/api/importer"
+	"kong-anime-go/internal/api/label"
+	"kong-anime-go/internal/api/person"
 	"kong-anime-go/internal/api/ping"
+	"kong-anime-go/internal/api/recategorize"
+	"kong-anime-go/internal/api/studio"
+	"kong-anime-go/internal/api/synonym"
 	"kong-anime-go/internal/api/tag"
+	"kong-anime-go/internal/config"
 	"kong-anime-go/internal/dao"
+	"kong-anime-go/internal/metadata/bangumi"
 	animesrv "kong-anime-go/internal/services/anime"
+	authsrv "kong-anime-go/internal/services/auth"
+	backupsrv "kong-anime-go/internal/services/backup"
 	categorysrv "kong-anime-go/internal/services/category"
+	enrichsrv "kong-anime-go/internal/services/enrich"
+	exportersrv "kong-anime-go/internal/services/exporter"
 	followsrv "kong-anime-go/internal/services/follow" // 添加追番服务的导入
+	importersrv "kong-anime-go/internal/services/importer"
+	personsrv "kong-anime-go/internal/services/person"
 	pingsrv "kong-anime-go/internal/services/ping"
+	recategorizesrv "kong-anime-go/internal/services/recategorize"
+	studiosrv "kong-anime-go/internal/services/studio"
+	synonymsrv "kong-anime-go/internal/services/synonym"
 	tagsrv "kong-anime-go/internal/services/tag"
 
 	"kong-anime-go/internal/middleware"
@@ -19,23 +43,34 @@ import (
 	"gorm.io/gorm"
 )
 
-func SetupRouter(db *gorm.DB) *gin.Engine {
+// SetupRouter 初始化路由，后台任务在 ctx 取消时停止
+func SetupRouter(ctx context.Context, db *gorm.DB) *gin.Engine {
 	router := gin.Default()
 
 	router.Use(middleware.CORSMiddleware())
+	router.Use(middleware.LanguageMiddleware())
 
-	// Ping
-	pingSrv := pingsrv.NewService()
-	pingHandler := ping.NewHandler(pingSrv)
+	// Auth
+	authSrv, err := authsrv.NewService(dao.NewUserDAO(db), config.Auth)
+	if err != nil {
+		log.Fatalf("invalid auth config: %v", err)
+	}
+	authHandler := auth.NewHandler(authSrv)
 
 	// Anime
 	animeDAO := dao.NewAnimeDAO(db)
 	categoryDAO := dao.NewCategoryDAO(db)
 	tagDAO := dao.NewTagDAO(db)
 	followDAO := dao.NewFollowDAO(db)
-	animeSrv := animesrv.NewService(animeDAO, categoryDAO, tagDAO, followDAO)
+	studioDAO := dao.NewStudioDAO(db)
+	animeSrv := animesrv.NewService(animeDAO, categoryDAO, tagDAO, followDAO, studioDAO)
 	animeHandler := anime.NewHandler(animeSrv)
 
+	// Enrich
+	bangumiClient := bangumi.NewClient(config.Metadata.BangumiBaseURL, config.Metadata.UserAgent, config.Metadata.Timeout)
+	enrichSrv := enrichsrv.NewService(animeSrv, bangumiClient)
+	enrichHandler := enrich.NewHandler(enrichSrv)
+
 	// Category
 	categorySrv := categorysrv.NewService(categoryDAO)
 	categoryHandler := category.NewHandler(categorySrv)
@@ -44,14 +79,79 @@ func SetupRouter(db *gorm.DB) *gin.Engine {
 	tagSrv := tagsrv.NewService(tagDAO)
 	tagHandler := tag.NewHandler(tagSrv)
 
+	// Synonym
+	synonymSrv := synonymsrv.NewService(dao.NewSynonymDAO(db), tagDAO, categoryDAO)
+	synonymHandler := synonym.NewHandler(synonymSrv)
+
+	// Studio
+	studioSrv := studiosrv.NewService(studioDAO, animeDAO)
+	studioHandler := studio.NewHandler(studioSrv)
+
+	// Person
+	personDAO := dao.NewPersonDAO(db)
+	movieDAO := dao.NewMovieDAO(db)
+	personSrv := personsrv.NewService(personDAO, animeDAO, movieDAO)
+	personHandler := person.NewHandler(personSrv)
+
 	// Follow
-	followSrv := followsrv.NewService(followDAO, animeDAO)
+	followCategoryDAO := dao.NewFollowCategoryDAO(db)
+	followSrv := followsrv.NewService(followDAO, animeDAO, followCategoryDAO)
 	followHandler := follow.NewHandler(followSrv)
 
-	v1 := router.Group("/api/v1")
+	// Export
+	exporterSrv := exportersrv.NewService(followDAO, followCategoryDAO)
+	exporterHandler := exporter.NewHandler(exporterSrv)
+
+	// Import
+	importDAO := dao.NewImportDAO(db)
+	importerSrv := importersrv.NewService(importDAO, animeDAO, animeSrv, followSrv)
+	importerHandler := importer.NewHandler(importerSrv)
+
+	// Backup
+	backupSrv := backupsrv.NewService(dao.NewBackupDAO(db))
+	backupScheduler, err := backupsrv.NewScheduler(backupSrv, config.Backup)
+	if err != nil {
+		log.Fatalf("invalid backup config: %v", err)
+	}
+	backupHandler := backup.NewHandler(backupSrv, backupScheduler)
+	if config.Backup.Enabled {
+		backupScheduler.Start(ctx)
+	}
+
+	// Ping
+	pingSrv := pingsrv.NewService(backupScheduler)
+	pingHandler := ping.NewHandler(pingSrv)
+
+	// Label
+	labelHandler := label.NewHandler()
+
+	// Recategorize
+	recategorizeRules, err := recategorizesrv.RulesFromConfig(config.Recategorize, followSrv.ValidateCategory)
+	if err != nil {
+		log.Fatalf("invalid recategorize config: %v", err)
+	}
+	recategorizeDAO := dao.NewRecategorizeDAO(db)
+	recategorizeSrv := recategorizesrv.NewService(followDAO, recategorizeDAO, animeSrv, recategorizeRules, followSrv.ValidateCategory)
+	followSrv.ReserveCategories(recategorizesrv.RuleCategories(recategorizeRules))
+	recategorizeHandler := recategorize.NewHandler(recategorizeSrv)
+	if config.Recategorize.Enabled {
+		recategorizeSrv.Start(ctx, config.Recategorize.Interval)
+	}
+
+	// 按路由权限表校验访问令牌和角色
+	v1 := router.Group("/api/v1", middleware.AuthMiddleware(authSrv, permissions, config.Auth.AnonymousRead))
 	{
 		v1.GET("/hello", pingHandler.GetHello)
 		v1.GET("/ping", pingHandler.GetPing)
+		v1.GET("/health", pingHandler.GetHealth)
+		v1.GET("/labels", labelHandler.GetAll)
+
+		// Auth
+		v1.POST("/auth/login", authHandler.Login)
+		v1.POST("/auth/refresh", authHandler.Refresh)
+		v1.POST("/auth/logout", authHandler.Logout)
+		v1.GET("/auth/me", authHandler.Me)
+		v1.PUT("/auth/password", authHandler.ChangePassword)
 
 		// Anime
 		v1.POST("/animes", animeHandler.Create)
@@ -66,6 +166,15 @@ func SetupRouter(db *gorm.DB) *gin.Engine {
 		v1.PATCH("/animes/:id/categories", animeHandler.AddCategoriesToAnime)
 		v1.PATCH("/animes/:id/tags", animeHandler.AddTagsToAnime)
 		v1.GET("/animes/seasons", animeHandler.GetAllSeasons)
+		v1.GET("/animes/duplicates", animeHandler.GetDuplicates)
+		v1.GET("/animes/merges", animeHandler.GetMerges)
+		v1.GET("/animes/:id/credits", personHandler.GetAnimeCredits)
+		v1.GET("/animes/by-external/:source/:id", animeHandler.GetByExternalID)
+		v1.POST("/animes/:id/external-ids", animeHandler.AddExternalID)
+		v1.DELETE("/animes/:id/external-ids/:source", animeHandler.DeleteExternalIDs)
+		v1.POST("/animes/:id/enrich", enrichHandler.Enrich)
+		v1.PUT("/animes/:id/locks", animeHandler.SetLockedFields)
+		v1.POST("/animes/:id/merge", animeHandler.Merge)
 
 		// Category
 		v1.POST("/categories", categoryHandler.Create)
@@ -75,6 +184,10 @@ func SetupRouter(db *gorm.DB) *gin.Engine {
 		v1.GET("/categories", categoryHandler.GetAll)
 		v1.GET("/categories/search", categoryHandler.GetByName)
 		v1.GET("/categories/stats", categoryHandler.GetStats)
+		v1.GET("/categories/:id/merge/preview", categoryHandler.PreviewMerge)
+		v1.POST("/categories/:id/merge", categoryHandler.Merge)
+		v1.PUT("/categories/:id/parent", categoryHandler.SetParent)
+		v1.PUT("/categories/order", categoryHandler.Reorder)
 
 		// Tag
 		v1.POST("/tags", tagHandler.Create)
@@ -84,6 +197,41 @@ func SetupRouter(db *gorm.DB) *gin.Engine {
 		v1.GET("/tags", tagHandler.GetAll)
 		v1.GET("/tags/search", tagHandler.GetByName)
 		v1.GET("/tags/stats", tagHandler.GetStats)
+		v1.GET("/tags/:id/merge/preview", tagHandler.PreviewMerge)
+		v1.POST("/tags/:id/merge", tagHandler.Merge)
+		v1.PUT("/tags/:id/parent", tagHandler.SetParent)
+		v1.PUT("/tags/order", tagHandler.Reorder)
+		v1.PUT("/tags/:id/group", tagHandler.SetGroup)
+
+		// Tag group
+		v1.GET("/tag-groups", tagHandler.GetGroups)
+		v1.POST("/tag-groups", tagHandler.CreateGroup)
+		v1.PUT("/tag-groups/:id", tagHandler.UpdateGroup)
+		v1.DELETE("/tag-groups/:id", tagHandler.DeleteGroup)
+		v1.GET("/tag-groups/violations", animeHandler.GetTagGroupViolations)
+
+		// Studio
+		v1.POST("/studios", studioHandler.Create)
+		v1.DELETE("/studios/:id", studioHandler.Delete)
+		v1.PUT("/studios/:id", studioHandler.Update)
+		v1.GET("/studios/:id", studioHandler.GetByID)
+		v1.GET("/studios", studioHandler.GetAll)
+		v1.GET("/studios/search", studioHandler.GetByName)
+		v1.GET("/studios/:id/animes", studioHandler.GetAnimes)
+		v1.GET("/studios/:id/stats", studioHandler.GetStats)
+		v1.GET("/studios/:id/merge/preview", studioHandler.PreviewMerge)
+		v1.POST("/studios/:id/merge", studioHandler.Merge)
+
+		// Person
+		v1.POST("/people", personHandler.Create)
+		v1.DELETE("/people/:id", personHandler.Delete)
+		v1.PUT("/people/:id", personHandler.Update)
+		v1.GET("/people/:id", personHandler.GetByID)
+		v1.GET("/people", personHandler.GetAll)
+		v1.GET("/people/:id/credits", personHandler.GetFilmography)
+		v1.POST("/credits", personHandler.CreateCredit)
+		v1.DELETE("/credits/:id", personHandler.DeleteCredit)
+		v1.GET("/people/stats", personHandler.GetWatchedStats)
 
 		// Follow
 		v1.POST("/follows", followHandler.Create)
@@ -93,6 +241,41 @@ func SetupRouter(db *gorm.DB) *gin.Engine {
 		v1.GET("/follows", followHandler.GetAll)
 		v1.PATCH("/follows/:id/status", followHandler.UpdateStatus)
 		v1.GET("/follows/categories", followHandler.GetAllCategories)
+		v1.POST("/follows/categories", followHandler.CreateCategory)
+		v1.PUT("/follows/categories/:value", followHandler.UpdateCategory)
+		v1.DELETE("/follows/categories/:value", followHandler.DeleteCategory)
+		v1.PUT("/follows/categories/order", followHandler.ReorderCategories)
+
+		// Export
+		v1.GET("/export/follows", exporterHandler.ExportFollows)
+
+		// Import
+		v1.POST("/imports/mal", importerHandler.ImportMAL)
+		v1.POST("/imports/csv", importerHandler.ImportCatalogue)
+		v1.GET("/imports", importerHandler.GetJobs)
+		v1.GET("/imports/:id", importerHandler.GetJob)
+
+		// Recategorize
+		v1.GET("/follows/recategorize/preview", recategorizeHandler.Preview)
+		v1.POST("/follows/recategorize", recategorizeHandler.Run)
+		v1.GET("/follows/recategorize/runs", recategorizeHandler.GetRuns)
+		v1.GET("/follows/recategorize/runs/:id", recategorizeHandler.GetRunByID)
+
+		// Admin
+		v1.GET("/admin/backup", backupHandler.Backup)
+		v1.POST("/admin/restore", backupHandler.Restore)
+		v1.GET("/admin/backups", backupHandler.GetLocalBackups)
+		v1.GET("/admin/synonyms", synonymHandler.GetAll)
+		v1.POST("/admin/synonyms", synonymHandler.Create)
+		v1.DELETE("/admin/synonyms/:id", synonymHandler.Delete)
+		v1.GET("/admin/synonyms/resolve", synonymHandler.Resolve)
+		v1.GET("/admin/users", authHandler.GetUsers)
+		v1.POST("/admin/users", authHandler.CreateUser)
+		v1.PUT("/admin/users/:id/role", authHandler.SetRole)
+	}
+
+	if err := permissions.Validate(router.Routes()); err != nil {
+		log.Fatalf("invalid route permissions: %v", err)
 	}
 
 	return router
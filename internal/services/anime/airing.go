@@ -0,0 +1,110 @@
+package anime
+
+import (
+	"sync"
+	"time"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao/models"
+)
+
+// airingRefreshInterval 自动推导的放送状态的刷新间隔
+const airingRefreshInterval = time.Hour
+
+// defaultCourWeeks 集数未知时按一季度（13周）估算放送周期
+const defaultCourWeeks = 13
+
+// DeriveAiringStatus 根据季度和集数推导放送状态
+// 按每周一集估算完结时间，集数未知时按一季度估算
+func DeriveAiringStatus(season string, episodes int, now time.Time) common.AiringStatus {
+	start, err := time.ParseInLocation("2006-01", season, now.Location())
+	if err != nil {
+		return common.AiringStatusUnknown
+	}
+	if now.Before(start) {
+		return common.AiringStatusUpcoming
+	}
+	weeks := episodes
+	if weeks <= 0 {
+		weeks = defaultCourWeeks
+	}
+	if now.Before(start.AddDate(0, 0, 7*weeks)) {
+		return common.AiringStatusAiring
+	}
+	return common.AiringStatusFinished
+}
+
+// airingRefresh 记录上次刷新放送状态的时间
+type airingRefresh struct {
+	mu          sync.Mutex // 保护放送状态的刷新
+	refreshedAt time.Time
+}
+
+// RefreshAiringStatuses 重新推导所有非手动指定的放送状态，返回发生变化的数量
+func (s *Service) RefreshAiringStatuses() (int, error) {
+	s.airing.mu.Lock()
+	defer s.airing.mu.Unlock()
+	return s.refreshAiringStatuses()
+}
+
+// refreshAiringStatusesIfStale 距离上次刷新超过间隔时重新推导放送状态，所有返回放送状态的读取都先调用
+func (s *Service) refreshAiringStatusesIfStale() error {
+	s.airing.mu.Lock()
+	defer s.airing.mu.Unlock()
+	if time.Since(s.airing.refreshedAt) < airingRefreshInterval {
+		return nil
+	}
+	_, err := s.refreshAiringStatuses()
+	return err
+}
+
+func (s *Service) refreshAiringStatuses() (int, error) {
+	animes, err := s.animeDAO.GetAutoAiringStatusAnimes()
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	changed := 0
+	for _, anime := range animes {
+		status := DeriveAiringStatus(anime.Season, anime.Episodes, now)
+		if status == anime.AiringStatus {
+			continue
+		}
+		if err := s.animeDAO.UpdateAiringStatus(anime.ID, status); err != nil {
+			return changed, err
+		}
+		changed++
+	}
+	s.airing.refreshedAt = now
+	return changed, nil
+}
+
+// GetSeasonAiringStatusCounts 获取每个季节各放送状态的动漫数量
+func (s *Service) GetSeasonAiringStatusCounts() (map[string]map[string]map[string]int, error) {
+	if err := s.refreshAiringStatusesIfStale(); err != nil {
+		return nil, err
+	}
+	counts, err := s.animeDAO.GetSeasonAiringStatusCounts()
+	if err != nil {
+		return nil, err
+	}
+
+	countMap := make(map[string]map[string]map[string]int)
+	for _, count := range counts {
+		addSeasonCount(countMap, count.Season, count.AiringStatus.String(), count.Count)
+	}
+	return countMap, nil
+}
+
+// EffectiveAiringStatus 返回动漫在 now 时刻的放送状态：手动指定时为指定的状态，否则根据季度和集数推导，不写入数据库
+func EffectiveAiringStatus(anime *models.Anime, now time.Time) common.AiringStatus {
+	if anime.AiringStatusManual {
+		return anime.AiringStatus
+	}
+	return DeriveAiringStatus(anime.Season, anime.Episodes, now)
+}
+
+// applyAiringStatus 非手动指定时根据季度和集数推导放送状态
+func applyAiringStatus(anime *models.Anime) {
+	anime.AiringStatus = EffectiveAiringStatus(anime, time.Now())
+}
@@ -2,31 +2,86 @@ package anime
 
 import (
 	"errors"
+	"fmt"
+	"kong-anime-go/internal/common"
 	"kong-anime-go/internal/dao"
 	"kong-anime-go/internal/dao/models"
+	"kong-anime-go/internal/i18n"
 	"strings"
+	"time"
+
+	"gorm.io/gorm"
 )
 
+// ErrExternalIDTaken 外部数据库ID已关联到其他动漫
+var ErrExternalIDTaken = errors.New("external id already linked to another anime")
+
 // Service 处理动漫相关的服务
 type Service struct {
 	animeDAO    *dao.AnimeDAO
 	categoryDAO *dao.CategoryDAO
 	tagDAO      *dao.TagDAO
 	followDAO   *dao.FollowDAO
+	studioDAO   *dao.StudioDAO
+
+	airing *airingRefresh // 放送状态的刷新记录，事务中的副本与原服务共用
 }
 
 // NewService 创建一个新的 AnimeService
-func NewService(animeDAO *dao.AnimeDAO, categoryDAO *dao.CategoryDAO, tagDAO *dao.TagDAO, followDAO *dao.FollowDAO) *Service {
+func NewService(animeDAO *dao.AnimeDAO, categoryDAO *dao.CategoryDAO, tagDAO *dao.TagDAO, followDAO *dao.FollowDAO, studioDAO *dao.StudioDAO) *Service {
 	return &Service{
 		animeDAO:    animeDAO,
 		categoryDAO: categoryDAO,
 		tagDAO:      tagDAO,
 		followDAO:   followDAO,
+		studioDAO:   studioDAO,
+		airing:      &airingRefresh{},
 	}
 }
 
-// Create 创建一个新的动漫
-func (s *Service) Create(anime *models.Anime, categories []string, tags []string) (*models.Anime, error) {
+// WithTx 返回使用事务 tx 的服务副本
+func (s *Service) WithTx(tx *gorm.DB) *Service {
+	txSrv := NewService(s.animeDAO.WithTx(tx), s.categoryDAO.WithTx(tx), s.tagDAO.WithTx(tx), s.followDAO.WithTx(tx), s.studioDAO.WithTx(tx))
+	txSrv.airing = s.airing
+	return txSrv
+}
+
+// Transaction 在同一个事务中执行 fn，fn 返回错误时回滚
+func (s *Service) Transaction(fn func(txSrv *Service) error) error {
+	return s.animeDAO.Transaction(func(tx *gorm.DB) error {
+		return fn(s.WithTx(tx))
+	})
+}
+
+// Create 创建一个新的动漫，标签需要符合标签组规则
+// 存在可能重复的动漫时返回 ErrPossibleDuplicate，force 为 true 时跳过重复检查
+func (s *Service) Create(anime *models.Anime, categories []string, tags []string, force bool) (*models.Anime, error) {
+	if err := s.checkTagGroups(tags); err != nil {
+		return nil, err
+	}
+	if !force {
+		duplicates, err := s.FindDuplicates(anime)
+		if err != nil {
+			return nil, err
+		}
+		if len(duplicates) > 0 {
+			return nil, &ErrPossibleDuplicate{Candidates: duplicates}
+		}
+	}
+	return s.create(anime, categories, tags)
+}
+
+// CreateStub 创建只有基本信息、尚未整理标签的动漫（如导入追番时按外部数据创建的条目），不检查标签组规则，
+// 违反规则的动漫可以通过标签组违规报告找到
+func (s *Service) CreateStub(anime *models.Anime) (*models.Anime, error) {
+	return s.create(anime, nil, nil)
+}
+
+func (s *Service) create(anime *models.Anime, categories []string, tags []string) (*models.Anime, error) {
+	if err := ValidateMediaType(anime.MediaType, anime.Episodes); err != nil {
+		return nil, err
+	}
+	applyAiringStatus(anime)
 	if err := s.animeDAO.Create(anime); err != nil {
 		return nil, err
 	}
@@ -36,6 +91,9 @@ func (s *Service) Create(anime *models.Anime, categories []string, tags []string
 	if err := s.addTagsToAnime(anime, tags); err != nil {
 		return nil, err
 	}
+	if err := s.addStudiosToAnime(anime); err != nil {
+		return nil, err
+	}
 	return s.animeDAO.GetByID(anime.ID)
 }
 
@@ -46,11 +104,11 @@ func (s *Service) Delete(id uint, force bool) (uint, error) {
 			return 0, err
 		}
 	} else {
-		follow, err := s.followDAO.GetByAnimeID(id)
+		_, follows, err := s.followDAO.GetFollowsByAnimeID(id)
 		if err != nil {
 			return 0, err
 		}
-		if follow != nil {
+		if follows > 0 {
 			return 0, errors.New("cannot delete anime with associated follow")
 		}
 	}
@@ -60,12 +118,21 @@ func (s *Service) Delete(id uint, force bool) (uint, error) {
 	if err := s.animeDAO.ClearTags(id); err != nil {
 		return 0, err
 	}
+	if err := s.animeDAO.ClearStudios(id); err != nil {
+		return 0, err
+	}
+	if err := s.animeDAO.ClearCredits(id); err != nil {
+		return 0, err
+	}
+	if err := s.animeDAO.ClearExternalIDs(id); err != nil {
+		return 0, err
+	}
 	// 硬删除
 	return id, s.animeDAO.HardDelete(id)
 }
 
-// Update 更新一个动漫
-func (s *Service) Update(anime *models.Anime, categories []string, tags []string) (*models.Anime, error) {
+// Update 更新一个动漫，标签需要符合标签组规则
+func (s *Service) Update(anime *models.Anime, categories []string, tags []string, autoAiringStatus bool) (*models.Anime, error) {
 	existingAnime, err := s.animeDAO.GetByID(anime.ID)
 	if err != nil {
 		return nil, err
@@ -73,6 +140,14 @@ func (s *Service) Update(anime *models.Anime, categories []string, tags []string
 	if existingAnime == nil {
 		return nil, errors.New("anime not found")
 	}
+	if err := ValidateMediaType(anime.MediaType, anime.Episodes); err != nil {
+		return nil, err
+	}
+	if err := s.checkTagGroups(tags); err != nil {
+		return nil, err
+	}
+	// 制作公司的关联只在 Production 文本变化时重建，合并制作公司后移动的关联不会因为其他修改而丢失
+	productionChanged := existingAnime.Production != anime.Production
 
 	existingAnime.Name = anime.Name
 	existingAnime.Aliases = anime.Aliases
@@ -80,6 +155,16 @@ func (s *Service) Update(anime *models.Anime, categories []string, tags []string
 	existingAnime.Season = anime.Season
 	existingAnime.Episodes = anime.Episodes
 	existingAnime.Image = anime.Image
+	existingAnime.MediaType = anime.MediaType
+	// 指定了放送状态时改为手动指定，autoAiringStatus 为 true 时恢复自动推导，否则保留原有的设置
+	switch {
+	case anime.AiringStatusManual:
+		existingAnime.AiringStatus = anime.AiringStatus
+		existingAnime.AiringStatusManual = true
+	case autoAiringStatus:
+		existingAnime.AiringStatusManual = false
+	}
+	applyAiringStatus(existingAnime)
 
 	if err := s.updateCategories(existingAnime, categories); err != nil {
 		return nil, err
@@ -87,6 +172,11 @@ func (s *Service) Update(anime *models.Anime, categories []string, tags []string
 	if err := s.updateTags(existingAnime, tags); err != nil {
 		return nil, err
 	}
+	if productionChanged {
+		if err := s.updateStudios(existingAnime); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := s.animeDAO.Update(existingAnime); err != nil {
 		return nil, err
@@ -109,6 +199,27 @@ func (s *Service) updateTags(anime *models.Anime, tags []string) error {
 	return s.addTagsToAnime(anime, tags)
 }
 
+func (s *Service) updateStudios(anime *models.Anime) error {
+	if err := s.animeDAO.ClearStudios(anime.ID); err != nil {
+		return err
+	}
+	return s.addStudiosToAnime(anime)
+}
+
+// addStudiosToAnime 根据 Production 文本关联制作公司
+func (s *Service) addStudiosToAnime(anime *models.Anime) error {
+	for _, name := range common.SplitNames(anime.Production) {
+		studio, err := s.studioDAO.GetOrCreate(name)
+		if err != nil {
+			return err
+		}
+		if err := s.animeDAO.AddStudio(anime.ID, studio.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *Service) addCategoriesToAnime(anime *models.Anime, categories []string) error {
 	for _, categoryName := range categories {
 		category, err := s.getOrCreateCategory(categoryName)
@@ -135,55 +246,150 @@ func (s *Service) addTagsToAnime(anime *models.Anime, tags []string) error {
 	return nil
 }
 
+// getOrCreateCategory 将名称解析为规范的分类（包括同义词和大小写、全角半角、繁简体不同的写法），解析不到时创建新分类
 func (s *Service) getOrCreateCategory(name string) (*models.Category, error) {
-	category, err := s.categoryDAO.GetByName(name)
-	if err != nil {
+	category, err := s.categoryDAO.GetByCanonicalName(name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
 		category = &models.Category{Name: name}
 		if err := s.categoryDAO.Create(category); err != nil {
 			return nil, err
 		}
+	} else if err != nil {
+		return nil, err
 	}
 	return category, nil
 }
 
+// getOrCreateTag 将名称解析为规范的标签（包括同义词和大小写、全角半角、繁简体不同的写法），解析不到时创建新标签
 func (s *Service) getOrCreateTag(name string) (*models.Tag, error) {
-	tag, err := s.tagDAO.GetByName(name)
-	if err != nil {
+	tag, err := s.tagDAO.GetByCanonicalName(name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
 		tag = &models.Tag{Name: name}
 		if err := s.tagDAO.Create(tag); err != nil {
 			return nil, err
 		}
+	} else if err != nil {
+		return nil, err
 	}
 	return tag, nil
 }
 
+// FormatSeason 将 202401、2024-01、20241、2024-1 等格式的季度统一为 2024-01
+func FormatSeason(season string) (string, error) {
+	formats := []string{"200601", "2006-01", "20061", "2006-1"}
+	for _, format := range formats {
+		t, err := time.Parse(format, season)
+		if err == nil {
+			return t.Format("2006-01"), nil
+		}
+	}
+	return "", errors.New("invalid season format")
+}
+
+// ValidateMediaType 检查动漫类型及其集数是否合法
+func ValidateMediaType(mediaType common.MediaType, episodes int) error {
+	if !mediaType.IsValid() {
+		return errors.New("invalid media type")
+	}
+	if !mediaType.ValidEpisodes(episodes) {
+		return i18n.Errorf("episodes %d not allowed for media type %s (1-%d)", episodes, mediaType, mediaType.MaxEpisodes())
+	}
+	return nil
+}
+
 // GetByID 根据ID获取动漫
 func (s *Service) GetByID(id uint) (*models.Anime, error) {
+	if err := s.refreshAiringStatusesIfStale(); err != nil {
+		return nil, err
+	}
 	return s.animeDAO.GetByID(id)
 }
 
+// GetByExternalID 根据外部数据库中的ID获取动漫，找不到时返回 nil
+func (s *Service) GetByExternalID(source common.ExternalSource, externalID string) (*models.Anime, error) {
+	if !source.IsValid() {
+		return nil, errors.New("invalid external source")
+	}
+	if err := s.refreshAiringStatusesIfStale(); err != nil {
+		return nil, err
+	}
+	return s.animeDAO.GetByExternalID(source, externalID)
+}
+
+// AddExternalID 为动漫添加外部数据库中的ID
+func (s *Service) AddExternalID(animeID uint, source common.ExternalSource, externalID string) (*models.Anime, error) {
+	if !source.IsValid() {
+		return nil, errors.New("invalid external source")
+	}
+	externalID = strings.TrimSpace(externalID)
+	if externalID == "" {
+		return nil, errors.New("external id is required")
+	}
+	if _, err := s.animeDAO.GetByID(animeID); err != nil {
+		return nil, err
+	}
+	linked, err := s.animeDAO.GetByExternalID(source, externalID)
+	if err != nil {
+		return nil, err
+	}
+	if linked != nil {
+		if linked.ID == animeID {
+			return linked, nil
+		}
+		return nil, fmt.Errorf("%w: %s", ErrExternalIDTaken, linked.Name)
+	}
+	ext := &models.ExternalID{AnimeID: animeID, Source: source, ExternalID: externalID}
+	if err := s.animeDAO.AddExternalID(ext); err != nil {
+		return nil, err
+	}
+	return s.GetByID(animeID)
+}
+
+// DeleteExternalIDs 删除动漫在指定外部数据库中的ID
+func (s *Service) DeleteExternalIDs(animeID uint, source common.ExternalSource) (*models.Anime, error) {
+	if err := s.animeDAO.DeleteExternalIDs(animeID, source); err != nil {
+		return nil, err
+	}
+	return s.GetByID(animeID)
+}
+
 // GetAll 获取所有动漫
-func (s *Service) GetAll(page, pageSize int) ([]models.Anime, int64, error) {
-	return s.animeDAO.GetAllPaginated(page, pageSize)
+func (s *Service) GetAll(page, pageSize int, filter dao.AnimeFilter) ([]models.Anime, int64, error) {
+	if err := s.refreshAiringStatusesIfStale(); err != nil {
+		return nil, 0, err
+	}
+	return s.animeDAO.GetAllPaginated(page, pageSize, filter)
 }
 
 // GetByName 根据名称获取动漫
 func (s *Service) GetByName(name string, page, pageSize int) ([]models.Anime, int64, error) {
+	if err := s.refreshAiringStatusesIfStale(); err != nil {
+		return nil, 0, err
+	}
 	return s.animeDAO.GetByNameAndAlias(name, page, pageSize)
 }
 
 // GetBySeason 根据季节获取动漫
 func (s *Service) GetBySeason(season string, page, pageSize int) ([]models.Anime, int64, error) {
+	if err := s.refreshAiringStatusesIfStale(); err != nil {
+		return nil, 0, err
+	}
 	return s.animeDAO.GetBySeason(season, page, pageSize)
 }
 
 // GetByCategory 根据分类获取动漫
 func (s *Service) GetByCategory(categoryName string, page, pageSize int) ([]models.Anime, int64, error) {
+	if err := s.refreshAiringStatusesIfStale(); err != nil {
+		return nil, 0, err
+	}
 	return s.animeDAO.GetByCategory(categoryName, page, pageSize)
 }
 
 // GetByTag 根据标签获取动漫
 func (s *Service) GetByTag(tagName string, page, pageSize int) ([]models.Anime, int64, error) {
+	if err := s.refreshAiringStatusesIfStale(); err != nil {
+		return nil, 0, err
+	}
 	return s.animeDAO.GetByTag(tagName, page, pageSize)
 }
 
@@ -196,7 +402,7 @@ func (s *Service) AddCategoriesToAnime(animeID uint, categories []string) (*mode
 	if err := s.updateCategories(anime, categories); err != nil {
 		return nil, err
 	}
-	return s.animeDAO.GetByID(anime.ID)
+	return s.GetByID(anime.ID)
 }
 
 // AddTagsToAnime 添加标签到动漫
@@ -205,10 +411,13 @@ func (s *Service) AddTagsToAnime(animeID uint, tags []string) (*models.Anime, er
 	if err != nil {
 		return nil, err
 	}
+	if err := s.checkTagGroups(tags); err != nil {
+		return nil, err
+	}
 	if err := s.updateTags(anime, tags); err != nil {
 		return nil, err
 	}
-	return s.animeDAO.GetByID(anime.ID)
+	return s.GetByID(anime.ID)
 }
 
 // GetAllSeasons 获取所有季节
@@ -232,3 +441,48 @@ func (s *Service) GetAllSeasons() (map[string]map[string]int, error) {
 	}
 	return seasonMap, nil
 }
+
+// GetMediaTypeCounts 获取符合筛选条件的各类型动漫数量
+func (s *Service) GetMediaTypeCounts(filter dao.AnimeFilter) (map[string]int, error) {
+	if err := s.refreshAiringStatusesIfStale(); err != nil {
+		return nil, err
+	}
+	counts, err := s.animeDAO.GetMediaTypeCounts(filter)
+	if err != nil {
+		return nil, err
+	}
+	countMap := make(map[string]int)
+	for _, count := range counts {
+		countMap[count.MediaType.String()] += count.Count
+	}
+	return countMap, nil
+}
+
+// GetSeasonMediaTypeCounts 获取每个季节各类型的动漫数量
+func (s *Service) GetSeasonMediaTypeCounts() (map[string]map[string]map[string]int, error) {
+	counts, err := s.animeDAO.GetSeasonMediaTypeCounts()
+	if err != nil {
+		return nil, err
+	}
+	countMap := make(map[string]map[string]map[string]int)
+	for _, count := range counts {
+		addSeasonCount(countMap, count.Season, count.MediaType.String(), count.Count)
+	}
+	return countMap, nil
+}
+
+// addSeasonCount 将季节计数按 年 -> 月 -> key 累加到 countMap
+func addSeasonCount(countMap map[string]map[string]map[string]int, season, key string, count int) {
+	parts := strings.Split(season, "-")
+	if len(parts) != 2 {
+		return
+	}
+	year, month := parts[0], parts[1]
+	if countMap[year] == nil {
+		countMap[year] = make(map[string]map[string]int)
+	}
+	if countMap[year][month] == nil {
+		countMap[year][month] = make(map[string]int)
+	}
+	countMap[year][month][key] += count
+}
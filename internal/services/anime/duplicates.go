@@ -0,0 +1,217 @@
+package anime
+
+import (
+	"sort"
+	"strings"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao/models"
+	"kong-anime-go/internal/i18n"
+)
+
+const (
+	// DuplicateThreshold 相似度不低于此值时视为可能重复
+	DuplicateThreshold = 0.85
+	// seasonMismatchPenalty 两者季度都已知但不同时扣除的相似度（可能是重制或续作）
+	seasonMismatchPenalty = 0.15
+)
+
+// DuplicateCandidate 可能重复的动漫
+type DuplicateCandidate struct {
+	AnimeID uint    `json:"anime_id"`
+	Name    string  `json:"name"`
+	Aliases string  `json:"aliases"`
+	Season  string  `json:"season"`
+	Score   float64 `json:"score"`
+	Reason  string  `json:"reason"` // 外部数据库ID（source:id）或匹配到的名称
+}
+
+// DuplicateCluster 一组可能互相重复的动漫
+type DuplicateCluster struct {
+	Animes []DuplicateCandidate `json:"animes"`
+	Score  float64              `json:"score"` // 组内最高的两两相似度
+}
+
+// ErrPossibleDuplicate 创建动漫时存在可能重复的已有动漫，可以强制创建
+type ErrPossibleDuplicate struct {
+	Candidates []DuplicateCandidate
+}
+
+func (e *ErrPossibleDuplicate) Error() string {
+	return e.Localize(i18n.En)
+}
+
+// Localize 返回 lang 中的错误信息
+func (e *ErrPossibleDuplicate) Localize(lang i18n.Lang) string {
+	names := make([]string, len(e.Candidates))
+	for i, candidate := range e.Candidates {
+		names[i] = candidate.Name
+	}
+	return i18n.Sprintf(lang, "possible duplicate of existing anime (%s)", strings.Join(names, ", "))
+}
+
+// duplicateEntry 预先归一化的动漫名称和别名
+type duplicateEntry struct {
+	anime *models.Anime
+	names []string
+	runes [][]rune
+}
+
+func newDuplicateEntry(anime *models.Anime) duplicateEntry {
+	entry := duplicateEntry{anime: anime}
+	for _, name := range append([]string{anime.Name}, common.SplitNames(anime.Aliases)...) {
+		normalized := []rune(common.NormalizeName(name))
+		if len(normalized) == 0 {
+			continue
+		}
+		entry.names = append(entry.names, name)
+		entry.runes = append(entry.runes, normalized)
+	}
+	return entry
+}
+
+// duplicateScore 计算两个动漫的相似度：名称和别名两两比较取最高值，季度不同时扣分
+// 返回 b 中匹配到的名称
+func duplicateScore(a, b duplicateEntry) (float64, string) {
+	best, reason := 0.0, ""
+	for _, ra := range a.runes {
+		for j, rb := range b.runes {
+			if !mayBeSimilar(ra, rb) {
+				continue
+			}
+			if score := common.NormalizedSimilarity(ra, rb); score > best {
+				best, reason = score, b.names[j]
+			}
+		}
+	}
+	if a.anime.Season != "" && b.anime.Season != "" && a.anime.Season != b.anime.Season {
+		best -= seasonMismatchPenalty
+	}
+	return best, reason
+}
+
+// mayBeSimilar 根据长度快速排除不可能达到阈值的名称：编辑距离不小于长度差
+func mayBeSimilar(a, b []rune) bool {
+	la, lb := len(a), len(b)
+	if la > lb {
+		la, lb = lb, la
+	}
+	return float64(la)/float64(lb) >= DuplicateThreshold
+}
+
+// FindDuplicates 查找与 anime 可能重复的已有动漫，按相似度从高到低排列
+// 外部数据库ID相同时直接视为重复
+func (s *Service) FindDuplicates(anime *models.Anime) ([]DuplicateCandidate, error) {
+	var candidates []DuplicateCandidate
+	seen := make(map[uint]bool)
+	for _, ext := range anime.ExternalIDs {
+		linked, err := s.animeDAO.GetByExternalID(ext.Source, ext.ExternalID)
+		if err != nil {
+			return nil, err
+		}
+		if linked != nil && linked.ID != anime.ID && !seen[linked.ID] {
+			seen[linked.ID] = true
+			candidates = append(candidates, toDuplicateCandidate(linked, 1, string(ext.Source)+":"+ext.ExternalID))
+		}
+	}
+
+	animes, err := s.animeDAO.GetNameIndex()
+	if err != nil {
+		return nil, err
+	}
+	entry := newDuplicateEntry(anime)
+	for i := range animes {
+		existing := &animes[i]
+		if existing.ID == anime.ID || seen[existing.ID] {
+			continue
+		}
+		if score, reason := duplicateScore(entry, newDuplicateEntry(existing)); score >= DuplicateThreshold {
+			candidates = append(candidates, toDuplicateCandidate(existing, score, reason))
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	return candidates, nil
+}
+
+// GetDuplicateClusters 在整个目录中查找可能重复的动漫，相似的动漫（可传递）归为一组
+func (s *Service) GetDuplicateClusters() ([]DuplicateCluster, error) {
+	animes, err := s.animeDAO.GetNameIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]duplicateEntry, len(animes))
+	for i := range animes {
+		entries[i] = newDuplicateEntry(&animes[i])
+	}
+
+	// 并查集
+	parent := make([]int, len(animes))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	best := make([]float64, len(animes)) // 每个动漫与组内其他动漫的最高相似度
+	reasons := make([]string, len(animes))
+	for i := range animes {
+		for j := i + 1; j < len(animes); j++ {
+			score, _ := duplicateScore(entries[i], entries[j])
+			if score < DuplicateThreshold {
+				continue
+			}
+			parent[find(j)] = find(i)
+			if score > best[i] {
+				best[i], reasons[i] = score, animes[j].Name
+			}
+			if score > best[j] {
+				best[j], reasons[j] = score, animes[i].Name
+			}
+		}
+	}
+
+	groups := make(map[int]*DuplicateCluster)
+	var roots []int
+	for i := range animes {
+		if best[i] == 0 {
+			continue
+		}
+		root := find(i)
+		cluster, ok := groups[root]
+		if !ok {
+			cluster = &DuplicateCluster{}
+			groups[root] = cluster
+			roots = append(roots, root)
+		}
+		cluster.Animes = append(cluster.Animes, toDuplicateCandidate(&animes[i], best[i], reasons[i]))
+		cluster.Score = max(cluster.Score, best[i])
+	}
+
+	clusters := make([]DuplicateCluster, 0, len(roots))
+	for _, root := range roots {
+		clusters = append(clusters, *groups[root])
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return clusters[i].Score > clusters[j].Score
+	})
+	return clusters, nil
+}
+
+func toDuplicateCandidate(anime *models.Anime, score float64, reason string) DuplicateCandidate {
+	return DuplicateCandidate{
+		AnimeID: anime.ID,
+		Name:    anime.Name,
+		Aliases: anime.Aliases,
+		Season:  anime.Season,
+		Score:   score,
+		Reason:  reason,
+	}
+}
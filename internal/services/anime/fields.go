@@ -0,0 +1,140 @@
+package anime
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"kong-anime-go/internal/dao/models"
+	"kong-anime-go/internal/i18n"
+)
+
+// 可以单独更新的动漫字段
+const (
+	FieldName       = "name"
+	FieldAliases    = "aliases"
+	FieldProduction = "production"
+	FieldSeason     = "season"
+	FieldEpisodes   = "episodes"
+	FieldImage      = "image"
+	FieldMediaType  = "media_type"
+)
+
+// Fields 所有可以单独更新的动漫字段
+var Fields = []string{FieldName, FieldAliases, FieldProduction, FieldSeason, FieldEpisodes, FieldImage, FieldMediaType}
+
+// ValidateFields 检查字段名是否合法
+func ValidateFields(fields []string) error {
+	for _, field := range fields {
+		if !slices.Contains(Fields, field) {
+			return i18n.Errorf("unknown field %q", field)
+		}
+	}
+	return nil
+}
+
+// copyField 将 src 中的字段复制到 dst
+func copyField(dst, src *models.Anime, field string) {
+	switch field {
+	case FieldName:
+		dst.Name = src.Name
+	case FieldAliases:
+		dst.Aliases = src.Aliases
+	case FieldProduction:
+		dst.Production = src.Production
+	case FieldSeason:
+		dst.Season = src.Season
+	case FieldEpisodes:
+		dst.Episodes = src.Episodes
+	case FieldImage:
+		dst.Image = src.Image
+	case FieldMediaType:
+		dst.MediaType = src.MediaType
+	}
+}
+
+// LockedFields 返回动漫被锁定的字段
+func LockedFields(anime *models.Anime) []string {
+	var fields []string
+	for _, field := range strings.Split(anime.LockedFields, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// IsLocked 判断动漫的字段是否被锁定
+func IsLocked(anime *models.Anime, field string) bool {
+	return slices.Contains(LockedFields(anime), field)
+}
+
+// SetLockedFields 设置动漫被锁定的字段（覆盖原有设置），被锁定的字段不会被自动写入修改
+func (s *Service) SetLockedFields(id uint, fields []string) (*models.Anime, error) {
+	if err := ValidateFields(fields); err != nil {
+		return nil, err
+	}
+	anime, err := s.animeDAO.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	// 按 Fields 的顺序保存，同时去重
+	var locked []string
+	for _, field := range Fields {
+		if slices.Contains(fields, field) {
+			locked = append(locked, field)
+		}
+	}
+	anime.LockedFields = strings.Join(locked, ",")
+	if err := s.animeDAO.Update(anime); err != nil {
+		return nil, err
+	}
+	return s.GetByID(id)
+}
+
+// UpdateFields 只更新动漫的指定字段，其余字段（包括分类和标签）保持不变
+// 供元数据补全、导入等自动写入使用：被锁定的字段会被跳过，并在 skipped 中返回
+func (s *Service) UpdateFields(id uint, values *models.Anime, fields []string) (anime *models.Anime, skipped []string, err error) {
+	if err := ValidateFields(fields); err != nil {
+		return nil, nil, err
+	}
+	existingAnime, err := s.animeDAO.GetByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if existingAnime == nil {
+		return nil, nil, errors.New("anime not found")
+	}
+
+	production := existingAnime.Production
+	var updated []string
+	for _, field := range fields {
+		if IsLocked(existingAnime, field) {
+			skipped = append(skipped, field)
+			continue
+		}
+		copyField(existingAnime, values, field)
+		updated = append(updated, field)
+	}
+	if len(updated) == 0 {
+		return existingAnime, skipped, nil
+	}
+	if existingAnime.Name == "" {
+		return nil, nil, errors.New("name is required")
+	}
+	if err := ValidateMediaType(existingAnime.MediaType, existingAnime.Episodes); err != nil {
+		return nil, nil, err
+	}
+	applyAiringStatus(existingAnime)
+
+	if existingAnime.Production != production {
+		if err := s.updateStudios(existingAnime); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := s.animeDAO.Update(existingAnime); err != nil {
+		return nil, nil, err
+	}
+	anime, err = s.animeDAO.GetByID(existingAnime.ID)
+	return anime, skipped, err
+}
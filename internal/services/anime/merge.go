@@ -0,0 +1,250 @@
+package anime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"kong-anime-go/internal/dao/models"
+	"kong-anime-go/internal/i18n"
+)
+
+// 两者都有追番时的处理方式
+const (
+	FollowStrategyTarget = "target" // 保留目标动漫的追番
+	FollowStrategySource = "source" // 保留源动漫的追番
+	FollowStrategyLatest = "latest" // 保留最近更新的追番
+)
+
+// ValidFollowStrategy 检查追番冲突的处理方式是否合法
+func ValidFollowStrategy(strategy string) bool {
+	return strategy == FollowStrategyTarget || strategy == FollowStrategySource || strategy == FollowStrategyLatest
+}
+
+// Merge 将源动漫合并到目标动漫并删除源动漫，所有操作在同一个事务中完成
+// 分类、标签、制作公司和别名取并集，担当和外部数据库ID移动到目标动漫，追番冲突按 followStrategy 处理
+// 返回合并后的目标动漫、合并记录以及因被锁定而跳过的字段
+func (s *Service) Merge(targetID, sourceID uint, followStrategy string) (*models.Anime, *models.AnimeMerge, []string, error) {
+	if targetID == sourceID {
+		return nil, nil, nil, errors.New("cannot merge anime into itself")
+	}
+	if followStrategy == "" {
+		followStrategy = FollowStrategyTarget
+	}
+	if !ValidFollowStrategy(followStrategy) {
+		return nil, nil, nil, i18n.Errorf("invalid follow strategy %q", followStrategy)
+	}
+
+	var anime *models.Anime
+	var skipped []string
+	merge := &models.AnimeMerge{TargetID: targetID, SourceID: sourceID, FollowStrategy: followStrategy}
+	err := s.Transaction(func(txSrv *Service) error {
+		target, err := txSrv.animeDAO.GetByID(targetID)
+		if err != nil {
+			return fmt.Errorf("target anime: %w", err)
+		}
+		source, err := txSrv.animeDAO.GetByID(sourceID)
+		if err != nil {
+			return fmt.Errorf("source anime: %w", err)
+		}
+		snapshot, err := json.Marshal(source)
+		if err != nil {
+			return err
+		}
+		merge.SourceName = source.Name
+		merge.SourceSnapshot = string(snapshot)
+
+		var details []string
+		added, err := txSrv.mergeAssociations(target, source)
+		if err != nil {
+			return err
+		}
+		details = append(details, added...)
+
+		moved, dropped, err := txSrv.animeDAO.MoveCredits(sourceID, targetID)
+		if err != nil {
+			return err
+		}
+		details = append(details, fmt.Sprintf("credits: moved %d, dropped %d duplicates", moved, dropped))
+
+		moved, dropped, err = txSrv.animeDAO.MoveExternalIDs(sourceID, targetID)
+		if err != nil {
+			return err
+		}
+		details = append(details, fmt.Sprintf("external ids: moved %d, dropped %d conflicting", moved, dropped))
+
+		followDetails, err := txSrv.mergeFollows(target, source, merge)
+		if err != nil {
+			return err
+		}
+		details = append(details, followDetails...)
+
+		if aliases := mergeAliases(target, source); aliases != target.Aliases {
+			_, skipped, err = txSrv.UpdateFields(targetID, &models.Anime{Aliases: aliases}, []string{FieldAliases})
+			if err != nil {
+				return err
+			}
+			if len(skipped) > 0 {
+				details = append(details, "aliases: skipped (locked)")
+			} else {
+				details = append(details, "aliases: "+aliases)
+			}
+		}
+
+		// 追番已经移动或删除，源动漫可以直接删除
+		if _, err := txSrv.Delete(sourceID, false); err != nil {
+			return err
+		}
+		merge.Details = strings.Join(details, "\n")
+		if err := txSrv.animeDAO.CreateMerge(merge); err != nil {
+			return err
+		}
+		anime, err = txSrv.GetByID(targetID)
+		return err
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return anime, merge, skipped, nil
+}
+
+// mergeAssociations 将源动漫的分类、标签和制作公司添加到目标动漫
+func (s *Service) mergeAssociations(target, source *models.Anime) ([]string, error) {
+	var categories, tags, studios int
+	merged := slices.Clone(target.Tags)
+	for _, tag := range source.Tags {
+		if !slices.ContainsFunc(merged, func(t models.Tag) bool { return t.ID == tag.ID }) {
+			merged = append(merged, tag)
+		}
+	}
+	if err := s.checkTagSetGroups(merged); err != nil {
+		return nil, err
+	}
+
+	for _, category := range source.Categories {
+		if slices.ContainsFunc(target.Categories, func(c models.Category) bool { return c.ID == category.ID }) {
+			continue
+		}
+		if err := s.animeDAO.AddCategory(target.ID, category.ID); err != nil {
+			return nil, err
+		}
+		categories++
+	}
+	for _, tag := range source.Tags {
+		if slices.ContainsFunc(target.Tags, func(t models.Tag) bool { return t.ID == tag.ID }) {
+			continue
+		}
+		if err := s.animeDAO.AddTag(target.ID, tag.ID); err != nil {
+			return nil, err
+		}
+		tags++
+	}
+	for _, studio := range source.Studios {
+		if slices.ContainsFunc(target.Studios, func(st models.Studio) bool { return st.ID == studio.ID }) {
+			continue
+		}
+		if err := s.animeDAO.AddStudio(target.ID, studio.ID); err != nil {
+			return nil, err
+		}
+		studios++
+	}
+	return []string{
+		fmt.Sprintf("categories: added %d", categories),
+		fmt.Sprintf("tags: added %d", tags),
+		fmt.Sprintf("studios: added %d", studios),
+	}, nil
+}
+
+// mergeFollows 按用户将源动漫的追番移动到目标动漫，同一用户两者都有追番时按策略保留一个并删除另一个
+// 只涉及一个用户时在合并记录中记录保留和删除的追番ID，涉及多个用户时只记录在明细中
+func (s *Service) mergeFollows(target, source *models.Anime, merge *models.AnimeMerge) ([]string, error) {
+	sourceFollows, _, err := s.followDAO.GetFollowsByAnimeID(source.ID)
+	if err != nil {
+		return nil, err
+	}
+	targetFollows, _, err := s.followDAO.GetFollowsByAnimeID(target.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(sourceFollows) == 0 && len(targetFollows) == 0 {
+		return []string{"follow: none"}, nil
+	}
+
+	// 每个用户在源动漫和目标动漫上各最多一条追番
+	type pair struct{ source, target *models.Follow }
+	pairs := make(map[uint]*pair)
+	var users []uint
+	get := func(userID uint) *pair {
+		if pairs[userID] == nil {
+			pairs[userID] = &pair{}
+			users = append(users, userID)
+		}
+		return pairs[userID]
+	}
+	for i := range sourceFollows {
+		get(sourceFollows[i].UserID).source = &sourceFollows[i]
+	}
+	for i := range targetFollows {
+		get(targetFollows[i].UserID).target = &targetFollows[i]
+	}
+	slices.Sort(users)
+
+	details := make([]string, 0, len(users))
+	for _, userID := range users {
+		p := pairs[userID]
+		detail, kept, deleted, err := s.mergeUserFollows(target, p.source, p.target, merge.FollowStrategy)
+		if err != nil {
+			return nil, err
+		}
+		if len(users) == 1 {
+			merge.KeptFollowID, merge.DeletedFollowID = kept, deleted
+		}
+		details = append(details, fmt.Sprintf("follow (user %d): %s", userID, detail))
+	}
+	return details, nil
+}
+
+// mergeUserFollows 合并同一用户在源动漫和目标动漫上的追番，返回明细以及保留和删除的追番ID
+func (s *Service) mergeUserFollows(target *models.Anime, sourceFollow, targetFollow *models.Follow, strategy string) (string, *uint, *uint, error) {
+	switch {
+	case sourceFollow == nil:
+		return fmt.Sprintf("kept %d", targetFollow.ID), &targetFollow.ID, nil, nil
+	case targetFollow == nil:
+		return fmt.Sprintf("moved %d", sourceFollow.ID), &sourceFollow.ID, nil, s.followDAO.UpdateAnimeID(sourceFollow.ID, target.ID)
+	}
+
+	keep, drop := targetFollow, sourceFollow
+	if strategy == FollowStrategySource ||
+		(strategy == FollowStrategyLatest && sourceFollow.UpdatedAt.After(targetFollow.UpdatedAt)) {
+		keep, drop = sourceFollow, targetFollow
+	}
+	if err := s.followDAO.HardDelete(drop.ID); err != nil {
+		return "", nil, nil, err
+	}
+	if keep.AnimeID != target.ID {
+		if err := s.followDAO.UpdateAnimeID(keep.ID, target.ID); err != nil {
+			return "", nil, nil, err
+		}
+	}
+	return fmt.Sprintf("kept %d, deleted %d (%s)", keep.ID, drop.ID, strategy), &keep.ID, &drop.ID, nil
+}
+
+// mergeAliases 合并别名：目标动漫的别名、源动漫的名称和别名，去除与目标动漫名称相同的项
+func mergeAliases(target, source *models.Anime) string {
+	candidates := append(strings.Split(target.Aliases, ","), source.Name)
+	candidates = append(candidates, strings.Split(source.Aliases, ",")...)
+	var aliases []string
+	for _, alias := range candidates {
+		if alias = strings.TrimSpace(alias); alias != "" && alias != target.Name && !slices.Contains(aliases, alias) {
+			aliases = append(aliases, alias)
+		}
+	}
+	return strings.Join(aliases, ",")
+}
+
+// GetMerges 获取合并记录，targetID 不为 0 时只返回合并到该动漫的记录
+func (s *Service) GetMerges(targetID uint, page, pageSize int) ([]models.AnimeMerge, int64, error) {
+	return s.animeDAO.GetMergesPaginated(targetID, page, pageSize)
+}
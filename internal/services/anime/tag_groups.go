@@ -0,0 +1,83 @@
+package anime
+
+import (
+	"errors"
+
+	"kong-anime-go/internal/dao/models"
+	tagsrv "kong-anime-go/internal/services/tag"
+
+	"gorm.io/gorm"
+)
+
+// TagGroupViolations 违反标签组规则的动漫
+type TagGroupViolations struct {
+	AnimeID    uint                    `json:"anime_id"`
+	AnimeName  string                  `json:"anime_name"`
+	Season     string                  `json:"season"`
+	Violations []tagsrv.GroupViolation `json:"violations"`
+}
+
+// checkTagGroups 检查标签名称解析到的标签是否符合标签组规则，尚不存在的标签不属于任何标签组
+func (s *Service) checkTagGroups(tagNames []string) error {
+	tags := make([]models.Tag, 0, len(tagNames))
+	for _, name := range tagNames {
+		tag, err := s.tagDAO.GetByCanonicalName(name)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		tags = append(tags, *tag)
+	}
+	return s.checkTagSetGroups(tags)
+}
+
+// checkTagSetGroups 检查一组已存在的标签是否符合标签组规则
+func (s *Service) checkTagSetGroups(tags []models.Tag) error {
+	groups, err := s.tagDAO.GetAllGroups()
+	if err != nil || len(groups) == 0 {
+		return err
+	}
+	if violations := tagsrv.CheckGroups(groups, tags); len(violations) > 0 {
+		return &tagsrv.ErrGroupViolation{Violations: violations}
+	}
+	return nil
+}
+
+// GetTagGroupViolations 列出标签不符合标签组规则的所有动漫
+func (s *Service) GetTagGroupViolations() ([]TagGroupViolations, error) {
+	groups, err := s.tagDAO.GetAllGroups()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]TagGroupViolations, 0)
+	if len(groups) == 0 {
+		return result, nil
+	}
+	rows, err := s.tagDAO.GetGroupedAnimeTags()
+	if err != nil {
+		return nil, err
+	}
+	tagsByAnime := make(map[uint][]models.Tag)
+	for _, row := range rows {
+		tag := models.Tag{Name: row.TagName, GroupID: &row.GroupID}
+		tag.ID = row.TagID
+		tagsByAnime[row.AnimeID] = append(tagsByAnime[row.AnimeID], tag)
+	}
+	animes, err := s.animeDAO.GetNameIndex()
+	if err != nil {
+		return nil, err
+	}
+	for _, anime := range animes {
+		if violations := tagsrv.CheckGroups(groups, tagsByAnime[anime.ID]); len(violations) > 0 {
+			result = append(result, TagGroupViolations{
+				AnimeID:    anime.ID,
+				AnimeName:  anime.Name,
+				Season:     anime.Season,
+				Violations: violations,
+			})
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,341 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/config"
+	"kong-anime-go/internal/dao"
+	"kong-anime-go/internal/dao/models"
+	"kong-anime-go/internal/i18n"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// MinPasswordLength 密码的最小长度
+const MinPasswordLength = 8
+
+// MinSecretLength 访问令牌签名密钥的最小长度（字节）
+const MinSecretLength = 32
+
+var (
+	// ErrInvalidCredentials 登录名或密码错误
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	// ErrInvalidToken 令牌无效或已过期
+	ErrInvalidToken = errors.New("invalid or expired token")
+	// ErrEmptyUsername 登录名为空
+	ErrEmptyUsername = errors.New("username must not be empty")
+	// ErrUsernameExists 登录名已被使用
+	ErrUsernameExists = errors.New("username already exists")
+	// ErrPasswordTooShort 密码太短
+	ErrPasswordTooShort = i18n.Errorf("password must be at least %d characters", MinPasswordLength)
+	// ErrWrongPassword 修改密码时当前密码错误
+	ErrWrongPassword = errors.New("current password is incorrect")
+	// ErrInvalidRole 角色不合法
+	ErrInvalidRole = errors.New("role must be viewer, editor or admin")
+	// ErrLastAdmin 不能取消最后一个管理员的管理员角色
+	ErrLastAdmin = errors.New("cannot demote the last admin")
+)
+
+// Tokens 登录或刷新后返回的令牌，访问令牌用于 Authorization: Bearer 请求头，刷新令牌只能使用一次
+type Tokens struct {
+	AccessToken      string       `json:"access_token"`
+	TokenType        string       `json:"token_type"`
+	ExpiresAt        time.Time    `json:"expires_at"`
+	RefreshToken     string       `json:"refresh_token"`
+	RefreshExpiresAt time.Time    `json:"refresh_expires_at"`
+	User             *models.User `json:"user"`
+}
+
+// Service 处理用户、登录和令牌相关的服务
+type Service struct {
+	userDAO    *dao.UserDAO
+	key        []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewService 创建一个新的 AuthService，未配置签名密钥时随机生成（重启后已签发的访问令牌失效）
+func NewService(userDAO *dao.UserDAO, cfg config.AuthConfig) (*Service, error) {
+	if cfg.AccessTTL <= 0 || cfg.RefreshTTL <= 0 {
+		return nil, errors.New("access_ttl and refresh_ttl must be positive")
+	}
+	key := []byte(cfg.JWTSecret)
+	if len(key) == 0 {
+		log.Printf("auth: jwt_secret is not set, using a random key; tokens will not survive a restart")
+		key = make([]byte, MinSecretLength)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+	}
+	if len(key) < MinSecretLength {
+		return nil, fmt.Errorf("jwt_secret must be at least %d bytes", MinSecretLength)
+	}
+	return &Service{
+		userDAO:    userDAO,
+		key:        key,
+		accessTTL:  cfg.AccessTTL,
+		refreshTTL: cfg.RefreshTTL,
+	}, nil
+}
+
+// CreateUser 创建用户，role 为空时第一个用户为管理员、其余为 viewer
+// 第一个用户会认领引入用户之前创建的追番，返回认领的追番数量
+func (s *Service) CreateUser(username, password, displayName string, role common.Role) (*models.User, int64, error) {
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return nil, 0, ErrEmptyUsername
+	}
+	if role != "" && !role.IsValid() {
+		return nil, 0, ErrInvalidRole
+	}
+	hash, err := hashPassword(password)
+	if err != nil {
+		return nil, 0, err
+	}
+	user := &models.User{Username: username, PasswordHash: hash, DisplayName: strings.TrimSpace(displayName), Role: role}
+	var claimed int64
+	err = s.userDAO.Transaction(func(txDAO *dao.UserDAO) error {
+		existing, err := txDAO.GetByUsername(username)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return ErrUsernameExists
+		}
+		count, err := txDAO.Count()
+		if err != nil {
+			return err
+		}
+		if user.Role == "" {
+			user.Role = common.RoleViewer
+			if count == 0 {
+				user.Role = common.RoleAdmin
+			}
+		}
+		if err := txDAO.Create(user); err != nil {
+			return err
+		}
+		if count == 0 {
+			claimed, err = txDAO.AssignOrphanFollows(user.ID)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return user, claimed, nil
+}
+
+// GetUsers 获取所有用户
+func (s *Service) GetUsers() ([]models.User, error) {
+	return s.userDAO.GetAll()
+}
+
+// SetRole 修改用户的角色，不能取消最后一个管理员的管理员角色
+func (s *Service) SetRole(id uint, role common.Role) (*models.User, error) {
+	if !role.IsValid() {
+		return nil, ErrInvalidRole
+	}
+	var user *models.User
+	err := s.userDAO.Transaction(func(txDAO *dao.UserDAO) error {
+		var err error
+		user, err = txDAO.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if user.Role == common.RoleAdmin && role != common.RoleAdmin {
+			admins, err := txDAO.CountByRole(common.RoleAdmin)
+			if err != nil {
+				return err
+			}
+			if admins <= 1 {
+				return ErrLastAdmin
+			}
+		}
+		user.Role = role
+		return txDAO.Update(user)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Login 校验登录名和密码，成功时签发访问令牌和刷新令牌
+func (s *Service) Login(username, password string) (*Tokens, error) {
+	user, err := s.userDAO.GetByUsername(strings.TrimSpace(username))
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return nil, ErrInvalidCredentials
+	}
+	if err := s.userDAO.DeleteExpiredRefreshTokens(time.Now()); err != nil {
+		return nil, err
+	}
+	return s.issueTokens(s.userDAO, user)
+}
+
+// Refresh 使用刷新令牌签发新的访问令牌和刷新令牌，旧的刷新令牌随即失效
+func (s *Service) Refresh(refreshToken string) (*Tokens, error) {
+	var tokens *Tokens
+	err := s.userDAO.Transaction(func(txDAO *dao.UserDAO) error {
+		hash := hashToken(refreshToken)
+		token, err := txDAO.GetRefreshToken(hash, time.Now())
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidToken
+		}
+		if err != nil {
+			return err
+		}
+		// 并发使用同一个刷新令牌时只有一个请求能删除成功
+		deleted, err := txDAO.DeleteRefreshToken(token.UserID, hash)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			return ErrInvalidToken
+		}
+		user, err := txDAO.GetByID(token.UserID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidToken
+		}
+		if err != nil {
+			return err
+		}
+		tokens, err = s.issueTokens(txDAO, user)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// Logout 使用户的刷新令牌失效，refreshToken 为空时使该用户的所有刷新令牌失效
+// 已签发的访问令牌在过期前仍然有效
+func (s *Service) Logout(userID uint, refreshToken string) error {
+	if refreshToken == "" {
+		return s.userDAO.DeleteRefreshTokensByUserID(userID)
+	}
+	_, err := s.userDAO.DeleteRefreshToken(userID, hashToken(refreshToken))
+	return err
+}
+
+// Authenticate 校验访问令牌并返回对应的用户，令牌无效、已过期或用户已不存在时返回 ErrInvalidToken
+func (s *Service) Authenticate(accessToken string) (*models.User, error) {
+	claims, err := parseJWT(s.key, accessToken, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	userID, err := claims.UserID()
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	// 每次都从数据库读取用户，角色的修改立即生效
+	user, err := s.userDAO.GetByID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidToken
+	}
+	return user, err
+}
+
+// ChangePassword 修改用户的密码，使该用户所有的刷新令牌失效并签发新的令牌
+func (s *Service) ChangePassword(userID uint, oldPassword, newPassword string) (*Tokens, error) {
+	user, err := s.userDAO.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(oldPassword)) != nil {
+		return nil, ErrWrongPassword
+	}
+	hash, err := hashPassword(newPassword)
+	if err != nil {
+		return nil, err
+	}
+	user.PasswordHash = hash
+	var tokens *Tokens
+	err = s.userDAO.Transaction(func(txDAO *dao.UserDAO) error {
+		if err := txDAO.Update(user); err != nil {
+			return err
+		}
+		if err := txDAO.DeleteRefreshTokensByUserID(userID); err != nil {
+			return err
+		}
+		tokens, err = s.issueTokens(txDAO, user)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// issueTokens 为用户签发访问令牌，并通过 userDAO 保存新的刷新令牌
+func (s *Service) issueTokens(userDAO *dao.UserDAO, user *models.User) (*Tokens, error) {
+	now := time.Now()
+	accessToken, err := signJWT(s.key, Claims{
+		Username: user.Username,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatUint(uint64(user.ID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.accessTTL)),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	stored := &models.RefreshToken{UserID: user.ID, TokenHash: hashToken(refreshToken), ExpiresAt: now.Add(s.refreshTTL)}
+	if err := userDAO.CreateRefreshToken(stored); err != nil {
+		return nil, err
+	}
+	return &Tokens{
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresAt:        time.Unix(now.Add(s.accessTTL).Unix(), 0),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: stored.ExpiresAt,
+		User:             user,
+	}, nil
+}
+
+// hashPassword 校验密码长度并生成 bcrypt 哈希
+func hashPassword(password string) (string, error) {
+	if len([]rune(password)) < MinPasswordLength {
+		return "", ErrPasswordTooShort
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// newToken 生成 32 字节的随机刷新令牌（十六进制）
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashToken 返回刷新令牌的 SHA-256 哈希，数据库中只保存哈希
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims 访问令牌中的声明，sub 为用户ID
+type Claims struct {
+	Username string `json:"name,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// UserID 返回声明中的用户ID
+func (c *Claims) UserID() (uint, error) {
+	id, err := strconv.ParseUint(c.Subject, 10, 64)
+	return uint(id), err
+}
+
+// signJWT 用 key 以 HS256 对声明签名
+func signJWT(key []byte, claims Claims) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+}
+
+// parseJWT 校验令牌的算法、签名和有效期，返回其中的声明
+// 只接受 HS256，防止 alg=none 或改用其他算法的令牌通过校验；令牌必须包含有效期
+func parseJWT(key []byte, token string, now time.Time) (*Claims, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) {
+		return key, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithTimeFunc(func() time.Time { return now }),
+	)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	return &claims, nil
+}
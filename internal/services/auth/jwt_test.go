@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/config"
+	"kong-anime-go/internal/dao"
+
+	"github.com/glebarez/sqlite"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testKey 测试用的签名密钥
+var testKey = []byte(strings.Repeat("k", MinSecretLength))
+
+// newTestService 创建使用内存数据库的认证服务
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 每个连接都是独立的内存数据库，只保留一个连接
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := dao.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+
+	srv, err := NewService(dao.NewUserDAO(db), config.AuthConfig{
+		JWTSecret:  string(testKey),
+		AccessTTL:  time.Minute,
+		RefreshTTL: time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srv
+}
+
+// testClaims 返回在 now 签发、一分钟后过期的声明
+func testClaims(now time.Time) Claims {
+	return Claims{
+		Username: "frieren",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "1",
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		},
+	}
+}
+
+// encodeSegment 以令牌使用的 base64url 编码 JSON 片段
+func encodeSegment(s string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
+
+func TestParseJWTRoundTrip(t *testing.T) {
+	now := time.Now()
+	token, err := signJWT(testKey, testClaims(now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := parseJWT(testKey, token, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := claims.UserID()
+	if err != nil || id != 1 || claims.Username != "frieren" {
+		t.Errorf("claims = %+v, user id %d (%v)", claims, id, err)
+	}
+}
+
+func TestParseJWTRejects(t *testing.T) {
+	now := time.Now()
+	valid, err := signJWT(testKey, testClaims(now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(valid, ".")
+
+	otherKey, err := signJWT([]byte(strings.Repeat("x", MinSecretLength)), testClaims(now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hs512, err := jwt.NewWithClaims(jwt.SigningMethodHS512, testClaims(now)).SignedString(testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	none, err := jwt.NewWithClaims(jwt.SigningMethodNone, testClaims(now)).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, err := signJWT(testKey, testClaims(now.Add(-2*time.Minute)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	noExpiry := testClaims(now)
+	noExpiry.ExpiresAt = nil
+	unbounded, err := signJWT(testKey, noExpiry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"signed with another key", otherKey},
+		{"tampered payload", parts[0] + "." + encodeSegment(`{"sub":"2","iat":0,"exp":9999999999}`) + "." + parts[2]},
+		{"stripped signature", parts[0] + "." + parts[1] + "."},
+		{"alg none", none},
+		{"alg none header on signed token", encodeSegment(`{"alg":"none","typ":"JWT"}`) + "." + parts[1] + "." + parts[2]},
+		{"alg HS512", hs512},
+		{"alg RS256 header", encodeSegment(`{"alg":"RS256","typ":"JWT"}`) + "." + parts[1] + "." + parts[2]},
+		{"expired", expired},
+		{"missing exp", unbounded},
+		{"malformed", "not-a-token"},
+		{"empty", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseJWT(testKey, tt.token, now); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("parseJWT err = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	srv := newTestService(t)
+	if _, _, err := srv.CreateUser("frieren", "password123", "", common.RoleAdmin); err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := srv.Login("frieren", "password123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := srv.Authenticate(tokens.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Username != "frieren" || user.Role != common.RoleAdmin {
+		t.Errorf("user = %s (%s), want frieren (admin)", user.Username, user.Role)
+	}
+
+	// 令牌中的用户不存在时拒绝
+	ghost := testClaims(time.Now())
+	ghost.Subject = "42"
+	token, err := signJWT(srv.key, ghost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srv.Authenticate(token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("unknown user err = %v, want ErrInvalidToken", err)
+	}
+
+	expired := testClaims(time.Now().Add(-2 * time.Minute))
+	token, err = signJWT(srv.key, expired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srv.Authenticate(token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expired token err = %v, want ErrInvalidToken", err)
+	}
+}
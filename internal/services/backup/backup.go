@@ -0,0 +1,560 @@
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao"
+	"kong-anime-go/internal/dao/models"
+
+	"gorm.io/gorm"
+)
+
+// Format 备份格式标识
+const Format = "kong-anime-go-backup"
+
+// SchemaVersion 当前备份的结构版本，备份内容变化时递增，恢复时兼容不高于此版本的备份
+//
+//	1: 初始版本
+//	2: 动漫增加 locked_fields
+//	3: 增加标签和分类的同义词 synonyms
+//	4: 标签和分类增加 parent_id
+//	5: 增加标签组 tag_groups，标签增加 group_id
+//	6: 标签和分类增加 description、color、icon、sort_order
+//	7: 增加追番分类 follow_categories
+//	8: 追番增加 dropped_at，追番状态增加搁置、弃番和重温
+//	9: 增加用户 users，追番增加 user_id
+const SchemaVersion = 9
+
+// manifestName 备份中的清单文件名，始终是归档中的第一个文件
+const manifestName = "manifest.json"
+
+// maxFileSize 备份中单个文件的大小上限，避免恶意归档耗尽内存
+const maxFileSize = 1 << 30
+
+var (
+	// ErrNotEmpty 恢复的目标数据库不为空
+	ErrNotEmpty = errors.New("database is not empty, restore requires an empty database")
+	// ErrInvalidArchive 备份文件无效
+	ErrInvalidArchive = errors.New("invalid backup archive")
+	// ErrUnsupportedVersion 备份结构版本不受支持
+	ErrUnsupportedVersion = errors.New("unsupported backup schema version")
+)
+
+// Manifest 备份清单
+type Manifest struct {
+	Format        string         `json:"format"`
+	SchemaVersion int            `json:"schema_version"`
+	CreatedAt     time.Time      `json:"created_at"`
+	Counts        map[string]int `json:"counts"` // 每张表的记录数
+}
+
+// Service 处理备份和恢复的服务
+type Service struct {
+	backupDAO *dao.BackupDAO
+}
+
+// NewService 创建一个新的 BackupService
+func NewService(backupDAO *dao.BackupDAO) *Service {
+	return &Service{backupDAO: backupDAO}
+}
+
+// FileName 生成备份文件名
+func FileName(t time.Time) string {
+	return fileNamePrefix + t.Format("20060102-150405") + fileNameSuffix
+}
+
+// Write 将整个数据库写入 w，格式为 tar.gz，其中依次包含清单和每张表的 JSONL 文件
+// 导入任务、自动归类记录和动漫合并记录等历史记录不包含在备份中
+func (s *Service) Write(w io.Writer) (*Manifest, error) {
+	manifest := &Manifest{
+		Format:        Format,
+		SchemaVersion: SchemaVersion,
+		CreatedAt:     time.Now(),
+		Counts:        make(map[string]int),
+	}
+
+	var files []archiveFile
+	add := func(name string, data []byte, count int, err error) error {
+		if err != nil {
+			return fmt.Errorf("backup %s: %w", name, err)
+		}
+		files = append(files, archiveFile{name: name + ".jsonl", data: data})
+		manifest.Counts[name] = count
+		return nil
+	}
+
+	err := errors.Join(
+		add(dumpTable(s.backupDAO, "categories", toCategoryRecord)),
+		add(dumpTable(s.backupDAO, "tag_groups", toTagGroupRecord)),
+		add(dumpTable(s.backupDAO, "tags", toTagRecord)),
+		add(dumpTable(s.backupDAO, "studios", toStudioRecord)),
+		add(dumpTable(s.backupDAO, "people", toPersonRecord)),
+		add(dumpTable(s.backupDAO, "movies", toMovieRecord)),
+		add(dumpTable(s.backupDAO, "animes", toAnimeRecord)),
+		add(dumpTable(s.backupDAO, "external_ids", toExternalIDRecord)),
+		add(dumpTable(s.backupDAO, "credits", toCreditRecord)),
+		add(dumpTable(s.backupDAO, "users", toUserRecord)),
+		add(dumpTable(s.backupDAO, "follow_categories", toFollowCategoryRecord)),
+		add(dumpTable(s.backupDAO, "follows", toFollowRecord)),
+		add(dumpTable(s.backupDAO, "synonyms", toSynonymRecord)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, jt := range joinTables {
+		joins, err := s.backupDAO.FindJoin(jt.Name, jt.OwnerCol, jt.TargetCol)
+		if err == nil {
+			var data []byte
+			data, err = encodeJSONL(joins)
+			err = add(jt.Name, data, len(joins), err)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	files = append([]archiveFile{{name: manifestName, data: manifestData}}, files...)
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	for _, f := range files {
+		header := &tar.Header{
+			Name:    f.name,
+			Mode:    0o644,
+			Size:    int64(len(f.data)),
+			ModTime: manifest.CreatedAt,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// Restore 将备份恢复到没有动漫目录和追番数据的数据库中，所有数据在同一个事务中写入
+// 记录会获得新的ID，关联和外键根据旧ID到新ID的映射重建；数据库中已有的用户会保留，备份中的同名用户合并到已有用户
+func (s *Service) Restore(r io.Reader) (*Manifest, error) {
+	manifest, files, err := readArchive(r)
+	if err != nil {
+		return nil, err
+	}
+
+	empty, err := s.isEmpty()
+	if err != nil {
+		return nil, err
+	}
+	if !empty {
+		return nil, ErrNotEmpty
+	}
+
+	err = s.backupDAO.Transaction(func(txDAO *dao.BackupDAO) error {
+		return restore(txDAO, files)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// Inspect 读取并校验备份，返回清单，不写入数据
+func Inspect(r io.Reader) (*Manifest, error) {
+	manifest, _, err := readArchive(r)
+	return manifest, err
+}
+
+// isEmpty 判断数据库中是否没有任何需要恢复的数据（包含软删除的记录）
+// 追番分类在迁移时写入默认数据，用户和刷新令牌在恢复时保留（通过接口恢复时至少有执行恢复的管理员），都不参与判断
+func (s *Service) isEmpty() (bool, error) {
+	for _, model := range []any{
+		&models.Category{}, &models.TagGroup{}, &models.Tag{}, &models.Studio{}, &models.Person{}, &models.Movie{},
+		&models.Anime{}, &models.ExternalID{}, &models.Credit{}, &models.Follow{}, &models.Synonym{},
+	} {
+		count, err := s.backupDAO.Count(model)
+		if err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// idMap 各表旧ID到新ID的映射
+type idMap map[string]map[uint]uint
+
+// lookup 查找 table 中旧ID对应的新ID
+func (m idMap) lookup(table string, oldID uint) (uint, error) {
+	newID, ok := m[table][oldID]
+	if !ok {
+		return 0, fmt.Errorf("unknown %s id %d", table, oldID)
+	}
+	return newID, nil
+}
+
+func (m idMap) lookupOptional(table string, oldID *uint) (*uint, error) {
+	if oldID == nil {
+		return nil, nil
+	}
+	newID, err := m.lookup(table, *oldID)
+	if err != nil {
+		return nil, err
+	}
+	return &newID, nil
+}
+
+// restore 按依赖顺序写入所有表
+func restore(txDAO *dao.BackupDAO, files map[string][]byte) error {
+	ids := make(idMap)
+	var err error
+	if ids["categories"], err = restoreTable(files, "categories", func(r categoryRecord) (uint, uint, error) {
+		m := r.model()
+		err := txDAO.Create(m)
+		return r.ID, m.ID, err
+	}); err != nil {
+		return err
+	}
+	if ids["tag_groups"], err = restoreTable(files, "tag_groups", func(r tagGroupRecord) (uint, uint, error) {
+		m := r.model()
+		err := txDAO.Create(m)
+		return r.ID, m.ID, err
+	}); err != nil {
+		return err
+	}
+	if ids["tags"], err = restoreTable(files, "tags", func(r tagRecord) (uint, uint, error) {
+		groupID, err := ids.lookupOptional("tag_groups", r.GroupID)
+		if err != nil {
+			return 0, 0, err
+		}
+		m := r.model()
+		m.GroupID = groupID
+		err = txDAO.Create(m)
+		return r.ID, m.ID, err
+	}); err != nil {
+		return err
+	}
+	// 父项可能在子项之后才创建，所有标签和分类创建后再设置父子关系
+	for _, table := range []string{"categories", "tags"} {
+		if err := restoreParents(txDAO, files, table, ids); err != nil {
+			return err
+		}
+	}
+	if ids["studios"], err = restoreTable(files, "studios", func(r studioRecord) (uint, uint, error) {
+		m := r.model()
+		err := txDAO.Create(m)
+		return r.ID, m.ID, err
+	}); err != nil {
+		return err
+	}
+	if ids["people"], err = restoreTable(files, "people", func(r personRecord) (uint, uint, error) {
+		m := r.model()
+		err := txDAO.Create(m)
+		return r.ID, m.ID, err
+	}); err != nil {
+		return err
+	}
+	if ids["movies"], err = restoreTable(files, "movies", func(r movieRecord) (uint, uint, error) {
+		m := r.model()
+		err := txDAO.Create(m)
+		return r.ID, m.ID, err
+	}); err != nil {
+		return err
+	}
+	if ids["animes"], err = restoreTable(files, "animes", func(r animeRecord) (uint, uint, error) {
+		m := r.model()
+		err := txDAO.Create(m)
+		return r.ID, m.ID, err
+	}); err != nil {
+		return err
+	}
+
+	for _, jt := range joinTables {
+		if _, err := restoreTable(files, jt.Name, func(r joinRecord) (uint, uint, error) {
+			owner, err := ids.lookup(jt.Owner, r[0])
+			if err != nil {
+				return 0, 0, err
+			}
+			target, err := ids.lookup(jt.Target, r[1])
+			if err != nil {
+				return 0, 0, err
+			}
+			return 0, 0, txDAO.CreateJoin(jt.Name, jt.OwnerCol, jt.TargetCol, owner, target)
+		}); err != nil {
+			return err
+		}
+	}
+
+	if _, err := restoreTable(files, "external_ids", func(r externalIDRecord) (uint, uint, error) {
+		animeID, err := ids.lookup("animes", r.AnimeID)
+		if err != nil {
+			return 0, 0, err
+		}
+		m := &models.ExternalID{Model: timestamps(r.CreatedAt, r.UpdatedAt), AnimeID: animeID, Source: r.Source, ExternalID: r.ExternalID}
+		err = txDAO.Create(m)
+		return r.ID, m.ID, err
+	}); err != nil {
+		return err
+	}
+	if _, err := restoreTable(files, "credits", func(r creditRecord) (uint, uint, error) {
+		personID, err := ids.lookup("people", r.PersonID)
+		if err != nil {
+			return 0, 0, err
+		}
+		animeID, err := ids.lookupOptional("animes", r.AnimeID)
+		if err != nil {
+			return 0, 0, err
+		}
+		movieID, err := ids.lookupOptional("movies", r.MovieID)
+		if err != nil {
+			return 0, 0, err
+		}
+		m := &models.Credit{
+			Model:     timestamps(r.CreatedAt, r.UpdatedAt),
+			PersonID:  personID,
+			AnimeID:   animeID,
+			MovieID:   movieID,
+			Role:      r.Role,
+			Character: r.Character,
+		}
+		err = txDAO.Create(m)
+		return r.ID, m.ID, err
+	}); err != nil {
+		return err
+	}
+	// 同名用户合并到数据库中已有的用户，保留已有用户的密码和角色
+	if ids["users"], err = restoreTable(files, "users", func(r userRecord) (uint, uint, error) {
+		var existing models.User
+		err := txDAO.First(&existing, "username = ?", r.Username)
+		if err == nil {
+			return r.ID, existing.ID, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, 0, err
+		}
+		if !r.Role.IsValid() {
+			return 0, 0, fmt.Errorf("%w: user %q has invalid role %q", ErrInvalidArchive, r.Username, r.Role)
+		}
+		m := r.model()
+		err = txDAO.Create(m)
+		return r.ID, m.ID, err
+	}); err != nil {
+		return err
+	}
+	// 追番分类在迁移时写入了默认数据，备份中有追番分类时以备份为准，分类值保持不变
+	if _, ok := files["follow_categories.jsonl"]; ok {
+		if err := txDAO.HardDeleteAll(&models.FollowCategory{}); err != nil {
+			return err
+		}
+	}
+	if _, err := restoreTable(files, "follow_categories", func(r followCategoryRecord) (uint, uint, error) {
+		m := r.model()
+		err := txDAO.Create(m)
+		return r.ID, m.ID, err
+	}); err != nil {
+		return err
+	}
+	if _, err := restoreTable(files, "follows", func(r followRecord) (uint, uint, error) {
+		animeID, err := ids.lookup("animes", r.AnimeID)
+		if err != nil {
+			return 0, 0, err
+		}
+		// 版本 9 之前的追番不属于任何用户，由第一个创建的用户认领
+		var userID uint
+		if r.UserID != 0 {
+			if userID, err = ids.lookup("users", r.UserID); err != nil {
+				return 0, 0, err
+			}
+		}
+		m := &models.Follow{
+			Model:      timestamps(r.CreatedAt, r.UpdatedAt),
+			UserID:     userID,
+			AnimeID:    animeID,
+			Category:   r.Category,
+			Status:     r.Status,
+			FinishedAt: r.FinishedAt,
+			DroppedAt:  r.DroppedAt,
+			Score:      r.Score,
+		}
+		err = txDAO.Create(m)
+		return r.ID, m.ID, err
+	}); err != nil {
+		return err
+	}
+	if _, err := restoreTable(files, "synonyms", func(r synonymRecord) (uint, uint, error) {
+		targetTable := "tags"
+		if r.Kind == common.SynonymKindCategory {
+			targetTable = "categories"
+		}
+		targetID, err := ids.lookup(targetTable, r.TargetID)
+		if err != nil {
+			return 0, 0, err
+		}
+		m := &models.Synonym{
+			Model:         timestamps(r.CreatedAt, r.UpdatedAt),
+			Kind:          string(r.Kind),
+			Name:          r.Name,
+			CanonicalName: r.CanonicalName,
+			TargetID:      targetID,
+		}
+		err = txDAO.Create(m)
+		return r.ID, m.ID, err
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// restoreParents 按旧ID的父子关系为已恢复的标签或分类设置父项
+func restoreParents(txDAO *dao.BackupDAO, files map[string][]byte, table string, ids idMap) error {
+	_, err := restoreTable(files, table, func(r struct {
+		ID       uint  `json:"id"`
+		ParentID *uint `json:"parent_id"`
+	}) (uint, uint, error) {
+		if r.ParentID == nil {
+			return 0, 0, nil
+		}
+		id, err := ids.lookup(table, r.ID)
+		if err != nil {
+			return 0, 0, err
+		}
+		parentID, err := ids.lookup(table, *r.ParentID)
+		if err != nil {
+			return 0, 0, err
+		}
+		return 0, 0, txDAO.SetParent(table, id, parentID)
+	})
+	return err
+}
+
+// archiveFile 归档中的一个文件
+type archiveFile struct {
+	name string
+	data []byte
+}
+
+// dumpTable 读取一张表并编码为 JSONL
+func dumpTable[M any, R any](backupDAO *dao.BackupDAO, name string, convert func(M) R) (string, []byte, int, error) {
+	var rows []M
+	if err := backupDAO.FindAll(&rows); err != nil {
+		return name, nil, 0, err
+	}
+	records := make([]R, len(rows))
+	for i, row := range rows {
+		records[i] = convert(row)
+	}
+	data, err := encodeJSONL(records)
+	return name, data, len(records), err
+}
+
+func encodeJSONL[R any](records []R) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// restoreTable 逐行解码一张表并调用 insert 写入，返回旧ID到新ID的映射
+// 备份中缺少该表时视为空表，以兼容旧版本的备份
+func restoreTable[R any](files map[string][]byte, name string, insert func(R) (oldID, newID uint, err error)) (map[uint]uint, error) {
+	ids := make(map[uint]uint)
+	data, ok := files[name+".jsonl"]
+	if !ok {
+		return ids, nil
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, maxFileSize)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var record R
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", name, line, err)
+		}
+		oldID, newID, err := insert(record)
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", name, line, err)
+		}
+		if oldID != 0 {
+			ids[oldID] = newID
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return ids, nil
+}
+
+// readArchive 读取备份并校验格式和结构版本
+func readArchive(r io.Reader) (*Manifest, map[string][]byte, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	defer gr.Close()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if header.Size > maxFileSize {
+			return nil, nil, fmt.Errorf("%w: %s is too large", ErrInvalidArchive, header.Name)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+		files[header.Name] = data
+	}
+
+	data, ok := files[manifestName]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: missing %s", ErrInvalidArchive, manifestName)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	if manifest.Format != Format {
+		return nil, nil, fmt.Errorf("%w: unknown format %q", ErrInvalidArchive, manifest.Format)
+	}
+	if manifest.SchemaVersion < 1 || manifest.SchemaVersion > SchemaVersion {
+		return nil, nil, fmt.Errorf("%w: %d (supported: 1-%d)", ErrUnsupportedVersion, manifest.SchemaVersion, SchemaVersion)
+	}
+	return &manifest, files, nil
+}
@@ -0,0 +1,400 @@
+package backup
+
+import (
+	"time"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao/models"
+
+	"gorm.io/gorm"
+)
+
+// 备份中的记录使用独立的结构体，字段名固定为 snake_case，与数据库实现和模型的 JSON 输出无关
+
+type categoryRecord struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"` // 版本 6 起
+	Color       string    `json:"color,omitempty"`       // 版本 6 起
+	Icon        string    `json:"icon,omitempty"`        // 版本 6 起
+	SortOrder   int       `json:"sort_order,omitempty"`  // 版本 6 起
+	ParentID    *uint     `json:"parent_id,omitempty"`   // 版本 4 起
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// tagGroupRecord 版本 5 起
+type tagGroupRecord struct {
+	ID          uint                  `json:"id"`
+	Name        string                `json:"name"`
+	Cardinality common.TagCardinality `json:"cardinality"`
+	Description string                `json:"description,omitempty"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+type tagRecord struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"` // 版本 6 起
+	Color       string    `json:"color,omitempty"`       // 版本 6 起
+	Icon        string    `json:"icon,omitempty"`        // 版本 6 起
+	SortOrder   int       `json:"sort_order,omitempty"`  // 版本 6 起
+	ParentID    *uint     `json:"parent_id,omitempty"`   // 版本 4 起
+	GroupID     *uint     `json:"group_id,omitempty"`    // 版本 5 起
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type studioRecord struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Aliases   string    `json:"aliases"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type personRecord struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Aliases   string    `json:"aliases"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type movieRecord struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	ReleaseYear int       `json:"release_year"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type animeRecord struct {
+	ID                 uint                `json:"id"`
+	Name               string              `json:"name"`
+	Aliases            string              `json:"aliases"`
+	Production         string              `json:"production"`
+	Season             string              `json:"season"`
+	Episodes           int                 `json:"episodes"`
+	Image              string              `json:"image"`
+	MediaType          common.MediaType    `json:"media_type"`
+	AiringStatus       common.AiringStatus `json:"airing_status"`
+	AiringStatusManual bool                `json:"airing_status_manual"`
+	LockedFields       string              `json:"locked_fields,omitempty"` // 版本 2 起
+	CreatedAt          time.Time           `json:"created_at"`
+	UpdatedAt          time.Time           `json:"updated_at"`
+}
+
+type externalIDRecord struct {
+	ID         uint                  `json:"id"`
+	AnimeID    uint                  `json:"anime_id"`
+	Source     common.ExternalSource `json:"source"`
+	ExternalID string                `json:"external_id"`
+	CreatedAt  time.Time             `json:"created_at"`
+	UpdatedAt  time.Time             `json:"updated_at"`
+}
+
+type creditRecord struct {
+	ID        uint              `json:"id"`
+	PersonID  uint              `json:"person_id"`
+	AnimeID   *uint             `json:"anime_id,omitempty"`
+	MovieID   *uint             `json:"movie_id,omitempty"`
+	Role      common.CreditRole `json:"role"`
+	Character string            `json:"character,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// followCategoryRecord 版本 7 起
+type followCategoryRecord struct {
+	ID          uint                  `json:"id"`
+	Value       common.FollowCategory `json:"value"`
+	Name        string                `json:"name"`
+	Description string                `json:"description,omitempty"`
+	Color       string                `json:"color,omitempty"`
+	SortOrder   int                   `json:"sort_order"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+// userRecord 版本 9 起，包含密码哈希，刷新令牌不备份
+type userRecord struct {
+	ID           uint        `json:"id"`
+	Username     string      `json:"username"`
+	PasswordHash string      `json:"password_hash"`
+	DisplayName  string      `json:"display_name,omitempty"`
+	Role         common.Role `json:"role"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
+
+type followRecord struct {
+	ID         uint                  `json:"id"`
+	UserID     uint                  `json:"user_id,omitempty"` // 版本 9 起，0 表示不属于任何用户
+	AnimeID    uint                  `json:"anime_id"`
+	Category   common.FollowCategory `json:"category"`
+	Status     common.FollowStatus   `json:"status"`
+	FinishedAt *time.Time            `json:"finished_at,omitempty"`
+	DroppedAt  *time.Time            `json:"dropped_at,omitempty"` // 版本 8 起
+	Score      *float64              `json:"score,omitempty"`
+	CreatedAt  time.Time             `json:"created_at"`
+	UpdatedAt  time.Time             `json:"updated_at"`
+}
+
+// synonymRecord 版本 3 起
+type synonymRecord struct {
+	ID            uint               `json:"id"`
+	Kind          common.SynonymKind `json:"kind"`
+	Name          string             `json:"name"`
+	CanonicalName string             `json:"canonical_name"`
+	TargetID      uint               `json:"target_id"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
+// joinRecord 多对多关联，依次为所有者ID和关联对象ID
+type joinRecord [2]uint
+
+// joinTable 描述一张多对多关联表
+type joinTable struct {
+	Name      string // 表名，同时作为备份中的文件名
+	OwnerCol  string
+	TargetCol string
+	Owner     string // 所有者所在的表
+	Target    string // 关联对象所在的表
+}
+
+// joinTables 需要备份的关联表
+var joinTables = []joinTable{
+	{Name: "anime_categories", OwnerCol: "anime_id", TargetCol: "category_id", Owner: "animes", Target: "categories"},
+	{Name: "anime_tags", OwnerCol: "anime_id", TargetCol: "tag_id", Owner: "animes", Target: "tags"},
+	{Name: "anime_studios", OwnerCol: "anime_id", TargetCol: "studio_id", Owner: "animes", Target: "studios"},
+	{Name: "movie_categories", OwnerCol: "movie_id", TargetCol: "category_id", Owner: "movies", Target: "categories"},
+	{Name: "movie_tags", OwnerCol: "movie_id", TargetCol: "tag_id", Owner: "movies", Target: "tags"},
+}
+
+func timestamps(createdAt, updatedAt time.Time) gorm.Model {
+	return gorm.Model{CreatedAt: createdAt, UpdatedAt: updatedAt}
+}
+
+func toCategoryRecord(m models.Category) categoryRecord {
+	return categoryRecord{
+		ID:          m.ID,
+		Name:        m.Name,
+		Description: m.Description,
+		Color:       m.Color,
+		Icon:        m.Icon,
+		SortOrder:   m.SortOrder,
+		ParentID:    m.ParentID,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+func (r categoryRecord) model() *models.Category {
+	return &models.Category{
+		Model:       timestamps(r.CreatedAt, r.UpdatedAt),
+		Name:        r.Name,
+		Description: r.Description,
+		Color:       r.Color,
+		Icon:        r.Icon,
+		SortOrder:   r.SortOrder,
+	}
+}
+
+func toTagGroupRecord(m models.TagGroup) tagGroupRecord {
+	return tagGroupRecord{
+		ID:          m.ID,
+		Name:        m.Name,
+		Cardinality: m.Cardinality,
+		Description: m.Description,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+func (r tagGroupRecord) model() *models.TagGroup {
+	return &models.TagGroup{Model: timestamps(r.CreatedAt, r.UpdatedAt), Name: r.Name, Cardinality: r.Cardinality, Description: r.Description}
+}
+
+func toTagRecord(m models.Tag) tagRecord {
+	return tagRecord{
+		ID:          m.ID,
+		Name:        m.Name,
+		Description: m.Description,
+		Color:       m.Color,
+		Icon:        m.Icon,
+		SortOrder:   m.SortOrder,
+		ParentID:    m.ParentID,
+		GroupID:     m.GroupID,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+func (r tagRecord) model() *models.Tag {
+	return &models.Tag{
+		Model:       timestamps(r.CreatedAt, r.UpdatedAt),
+		Name:        r.Name,
+		Description: r.Description,
+		Color:       r.Color,
+		Icon:        r.Icon,
+		SortOrder:   r.SortOrder,
+	}
+}
+
+func toStudioRecord(m models.Studio) studioRecord {
+	return studioRecord{ID: m.ID, Name: m.Name, Aliases: m.Aliases, CreatedAt: m.CreatedAt, UpdatedAt: m.UpdatedAt}
+}
+
+func (r studioRecord) model() *models.Studio {
+	return &models.Studio{Model: timestamps(r.CreatedAt, r.UpdatedAt), Name: r.Name, Aliases: r.Aliases}
+}
+
+func toPersonRecord(m models.Person) personRecord {
+	return personRecord{ID: m.ID, Name: m.Name, Aliases: m.Aliases, CreatedAt: m.CreatedAt, UpdatedAt: m.UpdatedAt}
+}
+
+func (r personRecord) model() *models.Person {
+	return &models.Person{Model: timestamps(r.CreatedAt, r.UpdatedAt), Name: r.Name, Aliases: r.Aliases}
+}
+
+func toMovieRecord(m models.Movie) movieRecord {
+	return movieRecord{ID: m.ID, Name: m.Name, ReleaseYear: m.ReleaseYear, CreatedAt: m.CreatedAt, UpdatedAt: m.UpdatedAt}
+}
+
+func (r movieRecord) model() *models.Movie {
+	return &models.Movie{Model: timestamps(r.CreatedAt, r.UpdatedAt), Name: r.Name, ReleaseYear: r.ReleaseYear}
+}
+
+func toAnimeRecord(m models.Anime) animeRecord {
+	return animeRecord{
+		ID:                 m.ID,
+		Name:               m.Name,
+		Aliases:            m.Aliases,
+		Production:         m.Production,
+		Season:             m.Season,
+		Episodes:           m.Episodes,
+		Image:              m.Image,
+		MediaType:          m.MediaType,
+		AiringStatus:       m.AiringStatus,
+		AiringStatusManual: m.AiringStatusManual,
+		LockedFields:       m.LockedFields,
+		CreatedAt:          m.CreatedAt,
+		UpdatedAt:          m.UpdatedAt,
+	}
+}
+
+func (r animeRecord) model() *models.Anime {
+	return &models.Anime{
+		Model:              timestamps(r.CreatedAt, r.UpdatedAt),
+		Name:               r.Name,
+		Aliases:            r.Aliases,
+		Production:         r.Production,
+		Season:             r.Season,
+		Episodes:           r.Episodes,
+		Image:              r.Image,
+		MediaType:          r.MediaType,
+		AiringStatus:       r.AiringStatus,
+		AiringStatusManual: r.AiringStatusManual,
+		LockedFields:       r.LockedFields,
+	}
+}
+
+func toExternalIDRecord(m models.ExternalID) externalIDRecord {
+	return externalIDRecord{
+		ID:         m.ID,
+		AnimeID:    m.AnimeID,
+		Source:     m.Source,
+		ExternalID: m.ExternalID,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
+
+func toCreditRecord(m models.Credit) creditRecord {
+	return creditRecord{
+		ID:        m.ID,
+		PersonID:  m.PersonID,
+		AnimeID:   m.AnimeID,
+		MovieID:   m.MovieID,
+		Role:      m.Role,
+		Character: m.Character,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+func toFollowCategoryRecord(m models.FollowCategory) followCategoryRecord {
+	return followCategoryRecord{
+		ID:          m.ID,
+		Value:       m.Value,
+		Name:        m.Name,
+		Description: m.Description,
+		Color:       m.Color,
+		SortOrder:   m.SortOrder,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+func (r followCategoryRecord) model() *models.FollowCategory {
+	return &models.FollowCategory{
+		Model:       timestamps(r.CreatedAt, r.UpdatedAt),
+		Value:       r.Value,
+		Name:        r.Name,
+		Description: r.Description,
+		Color:       r.Color,
+		SortOrder:   r.SortOrder,
+	}
+}
+
+func toUserRecord(m models.User) userRecord {
+	return userRecord{
+		ID:           m.ID,
+		Username:     m.Username,
+		PasswordHash: m.PasswordHash,
+		DisplayName:  m.DisplayName,
+		Role:         m.Role,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}
+}
+
+func (r userRecord) model() *models.User {
+	return &models.User{
+		Model:        timestamps(r.CreatedAt, r.UpdatedAt),
+		Username:     r.Username,
+		PasswordHash: r.PasswordHash,
+		DisplayName:  r.DisplayName,
+		Role:         r.Role,
+	}
+}
+
+func toFollowRecord(m models.Follow) followRecord {
+	return followRecord{
+		ID:         m.ID,
+		UserID:     m.UserID,
+		AnimeID:    m.AnimeID,
+		Category:   m.Category,
+		Status:     m.Status,
+		FinishedAt: m.FinishedAt,
+		DroppedAt:  m.DroppedAt,
+		Score:      m.Score,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
+
+func toSynonymRecord(m models.Synonym) synonymRecord {
+	return synonymRecord{
+		ID:            m.ID,
+		Kind:          common.SynonymKind(m.Kind),
+		Name:          m.Name,
+		CanonicalName: m.CanonicalName,
+		TargetID:      m.TargetID,
+		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
+	}
+}
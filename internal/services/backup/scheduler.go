@@ -0,0 +1,333 @@
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"kong-anime-go/internal/config"
+)
+
+// fileNamePrefix 和 fileNameSuffix 是 FileName 生成的文件名的前后缀，目录中只有这种文件会被列出和清理
+const (
+	fileNamePrefix = "kong-anime-go-"
+	fileNameSuffix = ".tar.gz"
+	checksumSuffix = ".sha256"
+)
+
+// LocalBackup 备份目录中的一份备份
+type LocalBackup struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	SHA256    string    `json:"sha256"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Retention 备份保留策略，两者都为 0 时保留所有备份
+type Retention struct {
+	KeepDaily  int `json:"keep_daily"`
+	KeepWeekly int `json:"keep_weekly"`
+}
+
+// ScheduleStatus 定时备份的状态
+type ScheduleStatus struct {
+	Enabled       bool       `json:"enabled"`
+	Schedule      string     `json:"schedule"`
+	Dir           string     `json:"dir"`
+	Retention     Retention  `json:"retention"`
+	NextRunAt     *time.Time `json:"next_run_at,omitempty"`
+	LastRunAt     *time.Time `json:"last_run_at,omitempty"`
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty"`
+	LastBackup    string     `json:"last_backup,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+}
+
+// Scheduler 按 cron 表达式将备份写入本地目录，并按保留策略清理旧备份
+type Scheduler struct {
+	service   *Service
+	dir       string
+	expr      string
+	schedule  *Schedule
+	retention Retention
+	enabled   bool
+
+	runMu  sync.Mutex // 避免同时执行多次备份
+	mu     sync.Mutex // 保护 status
+	status ScheduleStatus
+}
+
+// NewScheduler 根据配置创建定时备份
+func NewScheduler(service *Service, cfg config.BackupConfig) (*Scheduler, error) {
+	schedule, err := ParseSchedule(cfg.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Dir == "" {
+		return nil, errors.New("backup dir is required")
+	}
+	if cfg.KeepDaily < 0 || cfg.KeepWeekly < 0 {
+		return nil, errors.New("backup retention must not be negative")
+	}
+	return &Scheduler{
+		service:   service,
+		dir:       cfg.Dir,
+		expr:      cfg.Schedule,
+		schedule:  schedule,
+		retention: Retention{KeepDaily: cfg.KeepDaily, KeepWeekly: cfg.KeepWeekly},
+		enabled:   cfg.Enabled,
+	}, nil
+}
+
+// Start 启动定时备份，ctx 取消时停止
+func (s *Scheduler) Start(ctx context.Context) {
+	go func() {
+		for {
+			next := s.schedule.Next(time.Now())
+			if next.IsZero() {
+				log.Printf("backup: schedule %q never fires, scheduler stopped", s.expr)
+				return
+			}
+			s.mu.Lock()
+			s.status.NextRunAt = &next
+			s.mu.Unlock()
+
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+				if _, err := s.RunOnce(); err != nil {
+					log.Printf("backup: scheduled backup failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// RunOnce 立即写入一份备份并按保留策略清理旧备份，结果记录在状态中
+func (s *Scheduler) RunOnce() (*LocalBackup, error) {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	startedAt := time.Now()
+	backup, err := s.writeBackup(startedAt)
+	if err == nil {
+		var removed []string
+		removed, err = s.prune()
+		for _, name := range removed {
+			log.Printf("backup: removed %s by retention policy", name)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status.LastRunAt = &startedAt
+	if err != nil {
+		s.status.LastError = err.Error()
+		return backup, err
+	}
+	s.status.LastError = ""
+	s.status.LastSuccessAt = &startedAt
+	s.status.LastBackup = backup.Name
+	log.Printf("backup: wrote %s (%d bytes)", backup.Name, backup.Size)
+	return backup, nil
+}
+
+// writeBackup 先写入临时文件，完成后再重命名，避免留下不完整的备份；同时写入 sha256 校验文件
+func (s *Scheduler) writeBackup(now time.Time) (*LocalBackup, error) {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return nil, err
+	}
+	name := FileName(now)
+	path := filepath.Join(s.dir, name)
+	tmp, err := os.CreateTemp(s.dir, name+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	counter := &countingWriter{}
+	_, err = s.service.Write(io.MultiWriter(tmp, hash, counter))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, err
+	}
+
+	sum := hex.EncodeToString(hash.Sum(nil))
+	if err := os.WriteFile(path+checksumSuffix, []byte(sum+"  "+name+"\n"), 0o644); err != nil {
+		return nil, err
+	}
+	return &LocalBackup{Name: name, Size: counter.n, SHA256: sum, CreatedAt: now}, nil
+}
+
+// List 列出备份目录中的备份，按时间从新到旧排列
+func (s *Scheduler) List() ([]LocalBackup, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []LocalBackup{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	backups := make([]LocalBackup, 0, len(entries))
+	for _, entry := range entries {
+		createdAt, ok := parseFileName(entry.Name())
+		if !ok || !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		sum, err := s.checksum(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, LocalBackup{
+			Name:      entry.Name(),
+			Size:      info.Size(),
+			SHA256:    sum,
+			CreatedAt: createdAt,
+		})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+	return backups, nil
+}
+
+// checksum 读取备份的校验文件，没有校验文件时（例如手动放入的备份）重新计算
+func (s *Scheduler) checksum(name string) (string, error) {
+	path := filepath.Join(s.dir, name)
+	if data, err := os.ReadFile(path + checksumSuffix); err == nil {
+		if sum, _, _ := strings.Cut(string(data), " "); len(sum) == sha256.Size*2 {
+			return sum, nil
+		}
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// prune 按保留策略删除旧备份，返回被删除的文件名
+func (s *Scheduler) prune() ([]string, error) {
+	if s.retention.KeepDaily == 0 && s.retention.KeepWeekly == 0 {
+		return nil, nil
+	}
+	backups, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	keep := make(map[string]bool)
+	keepNewestPer(backups, keep, s.retention.KeepDaily, func(t time.Time) string {
+		return t.Format(time.DateOnly)
+	})
+	keepNewestPer(backups, keep, s.retention.KeepWeekly, func(t time.Time) string {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	})
+
+	var removed []string
+	for _, backup := range backups {
+		if keep[backup.Name] {
+			continue
+		}
+		path := filepath.Join(s.dir, backup.Name)
+		if err := os.Remove(path); err != nil {
+			return removed, err
+		}
+		if err := os.Remove(path + checksumSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, err
+		}
+		removed = append(removed, backup.Name)
+	}
+	return removed, nil
+}
+
+// keepNewestPer 在最近 n 个周期中，保留每个周期内最新的一份备份，backups 需按时间从新到旧排列
+func keepNewestPer(backups []LocalBackup, keep map[string]bool, n int, period func(time.Time) string) {
+	seen := make(map[string]bool)
+	for _, backup := range backups {
+		if len(seen) >= n {
+			return
+		}
+		key := period(backup.CreatedAt)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		keep[backup.Name] = true
+	}
+}
+
+// Status 返回定时备份的状态
+func (s *Scheduler) Status() ScheduleStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := s.status
+	status.Enabled = s.enabled
+	status.Schedule = s.expr
+	status.Dir = s.dir
+	status.Retention = s.retention
+	return status
+}
+
+// HealthName 健康检查项名称
+func (s *Scheduler) HealthName() string {
+	return "backup"
+}
+
+// HealthCheck 最近一次定时备份失败时返回错误
+func (s *Scheduler) HealthCheck() (any, error) {
+	status := s.Status()
+	if status.LastError != "" {
+		return status, errors.New(status.LastError)
+	}
+	return status, nil
+}
+
+// parseFileName 从 FileName 生成的文件名中解析备份时间
+func parseFileName(name string) (time.Time, bool) {
+	if !strings.HasPrefix(name, fileNamePrefix) || !strings.HasSuffix(name, fileNameSuffix) {
+		return time.Time{}, false
+	}
+	stamp := strings.TrimSuffix(strings.TrimPrefix(name, fileNamePrefix), fileNameSuffix)
+	t, err := time.ParseInLocation("20060102-150405", stamp, time.Local)
+	return t, err == nil
+}
+
+// countingWriter 统计写入的字节数
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
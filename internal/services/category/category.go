@@ -2,10 +2,48 @@ package category
 
 import (
 	"errors"
+	"fmt"
+	"kong-anime-go/internal/common"
 	"kong-anime-go/internal/dao"
 	"kong-anime-go/internal/dao/models"
+	"kong-anime-go/internal/i18n"
+	"slices"
 )
 
+var (
+	// ErrMergeIntoSelf 不能将分类合并到自身
+	ErrMergeIntoSelf = errors.New("cannot merge category into itself")
+	// ErrParentCycle 父分类不能是自身或自身的子分类
+	ErrParentCycle = errors.New("category cannot be placed under itself or its descendants")
+	// ErrTooDeep 分类层级超过上限
+	ErrTooDeep = i18n.Errorf("category hierarchy cannot be deeper than %d levels", common.MaxHierarchyDepth)
+	// ErrInvalidColor 颜色格式不合法
+	ErrInvalidColor = errors.New("color must be in #RGB or #RRGGBB format")
+	// ErrInvalidOrder 排序列表中有不存在或重复的分类
+	ErrInvalidOrder = errors.New("order must list existing categories without duplicates")
+)
+
+// ErrNameExists 已存在同名的分类，可以改为合并到该分类
+type ErrNameExists struct {
+	Existing *models.Category
+}
+
+func (e *ErrNameExists) Error() string {
+	return e.Localize(i18n.En)
+}
+
+// Localize 返回 lang 中的错误信息
+func (e *ErrNameExists) Localize(lang i18n.Lang) string {
+	return i18n.Sprintf(lang, "category %q already exists (id %d), merge into it instead", e.Existing.Name, e.Existing.ID)
+}
+
+// MergePreview 合并预览
+type MergePreview struct {
+	Source *models.Category `json:"source"`
+	Target *models.Category `json:"target"`
+	Impact *dao.MergeImpact `json:"impact"`
+}
+
 // Service 处理分类相关的服务
 type Service struct {
 	categoryDAO *dao.CategoryDAO
@@ -18,12 +56,18 @@ func NewService(categoryDAO *dao.CategoryDAO) *Service {
 	}
 }
 
-// Create 创建一个新的分类
+// Create 创建一个新的分类，名称能解析到已有分类（包括同义词和不同写法）时返回已有分类
 func (s *Service) Create(category *models.Category) (*models.Category, error) {
-	existingCategory, err := s.categoryDAO.GetByName(category.Name)
+	existingCategory, err := s.categoryDAO.GetByCanonicalName(category.Name)
 	if err == nil && existingCategory != nil {
 		return existingCategory, nil
 	}
+	if err := validateDisplay(category); err != nil {
+		return nil, err
+	}
+	if err := validateParent(s.categoryDAO, 0, category.ParentID); err != nil {
+		return nil, err
+	}
 	if err := s.categoryDAO.Create(category); err != nil {
 		return nil, err
 	}
@@ -40,8 +84,21 @@ func (s *Service) Delete(id uint) (uint, error) {
 	if relatedItems {
 		return 0, errors.New("cannot delete category with related items")
 	}
-	// 硬删除
-	if err := s.categoryDAO.HardDelete(id); err != nil {
+	existingCategory, err := s.categoryDAO.GetByID(id)
+	if err != nil {
+		return 0, err
+	}
+	// 硬删除，同时删除指向该分类的同义词，子分类移动到该分类的父分类下
+	err = s.categoryDAO.Transaction(func(txDAO *dao.CategoryDAO) error {
+		if err := txDAO.DeleteSynonyms(id); err != nil {
+			return err
+		}
+		if err := txDAO.ReparentChildren(id, existingCategory.ParentID); err != nil {
+			return err
+		}
+		return txDAO.HardDelete(id)
+	})
+	if err != nil {
 		return 0, err
 	}
 	return id, nil
@@ -56,12 +113,20 @@ func (s *Service) Update(category *models.Category) (*models.Category, error) {
 	if existingCategory == nil {
 		return nil, errors.New("category not found")
 	}
+	if err := validateDisplay(category); err != nil {
+		return nil, err
+	}
 
-	if existingCategory.Name == category.Name {
-		return existingCategory, nil
+	if existingCategory.Name != category.Name {
+		if other, err := s.categoryDAO.GetByCanonicalName(category.Name); err == nil && other.ID != existingCategory.ID {
+			return nil, &ErrNameExists{Existing: other}
+		}
 	}
 
 	existingCategory.Name = category.Name
+	existingCategory.Description = category.Description
+	existingCategory.Color = category.Color
+	existingCategory.Icon = category.Icon
 	if err := s.categoryDAO.Update(existingCategory); err != nil {
 		return nil, err
 	}
@@ -78,12 +143,176 @@ func (s *Service) GetAll() ([]models.Category, error) {
 	return s.categoryDAO.GetAll()
 }
 
+// GetTree 获取所有分类的树形结构，返回顶层分类，子分类按显示顺序填充在 Children 中
+func (s *Service) GetTree() ([]models.Category, error) {
+	categories, err := s.categoryDAO.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	children := make(map[uint][]models.Category)
+	for _, category := range categories {
+		if category.ParentID != nil {
+			children[*category.ParentID] = append(children[*category.ParentID], category)
+		}
+	}
+	var build func(category models.Category) models.Category
+	build = func(category models.Category) models.Category {
+		for _, child := range children[category.ID] {
+			category.Children = append(category.Children, build(child))
+		}
+		return category
+	}
+	roots := make([]models.Category, 0)
+	for _, category := range categories {
+		if category.ParentID == nil {
+			roots = append(roots, build(category))
+		}
+	}
+	return roots, nil
+}
+
+// Reorder 按 ids 的顺序排列分类，未列出的分类保持原有顺序排在后面
+func (s *Service) Reorder(ids []uint) ([]models.Category, error) {
+	categories, err := s.categoryDAO.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	listed := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if listed[id] || !slices.ContainsFunc(categories, func(category models.Category) bool { return category.ID == id }) {
+			return nil, fmt.Errorf("%w: %d", ErrInvalidOrder, id)
+		}
+		listed[id] = true
+	}
+	order := append([]uint{}, ids...)
+	for _, category := range categories {
+		if !listed[category.ID] {
+			order = append(order, category.ID)
+		}
+	}
+	err = s.categoryDAO.Transaction(func(txDAO *dao.CategoryDAO) error {
+		return txDAO.SetSortOrders(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.categoryDAO.GetAll()
+}
+
+// validateDisplay 检查分类的显示设置
+func validateDisplay(category *models.Category) error {
+	if category.Color != "" && !common.IsHexColor(category.Color) {
+		return ErrInvalidColor
+	}
+	return nil
+}
+
+// SetParent 将分类移动到 parentID 下，parentID 为 nil 时成为顶层分类
+func (s *Service) SetParent(id uint, parentID *uint) (*models.Category, error) {
+	category, err := s.categoryDAO.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateParent(s.categoryDAO, id, parentID); err != nil {
+		return nil, err
+	}
+	category.ParentID = parentID
+	if err := s.categoryDAO.Update(category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// validateParent 检查将分类 id（新建时为 0）放到 parentID 下是否会形成环或超过层级上限
+func validateParent(categoryDAO *dao.CategoryDAO, id uint, parentID *uint) error {
+	if parentID == nil {
+		return nil
+	}
+	if _, err := categoryDAO.GetByID(*parentID); err != nil {
+		return fmt.Errorf("parent category: %w", err)
+	}
+	h, err := categoryDAO.GetHierarchy()
+	if err != nil {
+		return err
+	}
+	height := 1
+	if id != 0 {
+		if slices.Contains(h.Descendants(id), *parentID) {
+			return ErrParentCycle
+		}
+		height = h.Height(id)
+	}
+	if h.Depth(*parentID)+height > common.MaxHierarchyDepth {
+		return ErrTooDeep
+	}
+	return nil
+}
+
 // GetByName 根据名称获取分类
 func (s *Service) GetByName(name string) ([]models.Category, error) {
 	return s.categoryDAO.GetByNameLike(name)
 }
 
-// GetStats 获取分类统计信息
-func (s *Service) GetStats() (map[string]int, error) {
+// GetStats 按显示顺序获取分类统计信息
+func (s *Service) GetStats() ([]dao.DisplayStat, error) {
 	return s.categoryDAO.GetCategoryStats()
 }
+
+// PreviewMerge 预览将源分类合并到目标分类时受影响的项目数量
+func (s *Service) PreviewMerge(sourceID, targetID uint) (*MergePreview, error) {
+	return previewMerge(s.categoryDAO, sourceID, targetID)
+}
+
+func previewMerge(categoryDAO *dao.CategoryDAO, sourceID, targetID uint) (*MergePreview, error) {
+	if sourceID == targetID {
+		return nil, ErrMergeIntoSelf
+	}
+	source, err := categoryDAO.GetByID(sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("source category: %w", err)
+	}
+	target, err := categoryDAO.GetByID(targetID)
+	if err != nil {
+		return nil, fmt.Errorf("target category: %w", err)
+	}
+	// 源分类的子分类合并后移动到目标分类下
+	h, err := categoryDAO.GetHierarchy()
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(h.Descendants(sourceID), targetID) {
+		return nil, ErrParentCycle
+	}
+	if h.Depth(targetID)+h.Height(sourceID)-1 > common.MaxHierarchyDepth {
+		return nil, ErrTooDeep
+	}
+	impact, err := categoryDAO.GetMergeImpact(sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return &MergePreview{Source: source, Target: target, Impact: impact}, nil
+}
+
+// Merge 将源分类合并到目标分类：移动并去重源分类关联的动漫和电影，将源分类的名称和同义词转为目标分类的同义词，
+// 将源分类的子分类移动到目标分类下，然后删除源分类，所有操作在同一个事务中完成
+func (s *Service) Merge(sourceID, targetID uint) (*MergePreview, error) {
+	var preview *MergePreview
+	err := s.categoryDAO.Transaction(func(txDAO *dao.CategoryDAO) error {
+		var err error
+		preview, err = previewMerge(txDAO, sourceID, targetID)
+		if err != nil {
+			return err
+		}
+		if err := txDAO.MoveItems(sourceID, targetID); err != nil {
+			return err
+		}
+		if err := txDAO.MoveSynonyms(preview.Source, preview.Target); err != nil {
+			return err
+		}
+		if err := txDAO.ReparentChildren(sourceID, &targetID); err != nil {
+			return err
+		}
+		return txDAO.HardDelete(sourceID)
+	})
+	return preview, err
+}
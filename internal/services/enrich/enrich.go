@@ -0,0 +1,226 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao/models"
+	"kong-anime-go/internal/metadata"
+	animesrv "kong-anime-go/internal/services/anime"
+)
+
+// minSimilarity 未指定条目ID时，搜索结果与动漫名称的最低相似度
+const minSimilarity = 0.6
+
+var (
+	// ErrUnknownSource 没有对应的元数据提供者
+	ErrUnknownSource = errors.New("no metadata provider for source")
+	// ErrNoMatch 搜索不到足够相似的条目
+	ErrNoMatch = errors.New("no matching metadata found, specify external_id")
+	// ErrProvider 请求元数据提供者失败
+	ErrProvider = errors.New("metadata provider failed")
+)
+
+// Request 补全请求
+type Request struct {
+	Source     common.ExternalSource // 为空时使用第一个提供者
+	ExternalID string                // 为空时使用动漫已关联的ID，仍为空时按名称搜索
+	Apply      []string              // 需要应用的字段，为空时只预览
+}
+
+// FieldDiff 一个字段的当前值和候选值
+type FieldDiff struct {
+	Field     string `json:"field"`
+	Current   any    `json:"current"`
+	Candidate any    `json:"candidate"`
+	Changed   bool   `json:"changed"`
+	Locked    bool   `json:"locked"` // 字段被锁定，应用时会被跳过
+}
+
+// Result 补全结果
+type Result struct {
+	Metadata   *metadata.Metadata  `json:"metadata"`
+	Candidates []metadata.Metadata `json:"candidates,omitempty"` // 按名称搜索时的所有搜索结果
+	Similarity float64             `json:"similarity,omitempty"` // 按名称搜索时选中条目的相似度
+	Diff       []FieldDiff         `json:"diff"`
+	Applied    []string            `json:"applied"`
+	Skipped    []string            `json:"skipped"` // 因为被锁定而跳过的字段
+	Anime      *models.Anime       `json:"anime"`
+}
+
+// Service 使用外部元数据补全动漫信息的服务
+type Service struct {
+	animeSrv  *animesrv.Service
+	providers []metadata.MetadataProvider
+}
+
+// NewService 创建一个新的 EnrichService
+func NewService(animeSrv *animesrv.Service, providers ...metadata.MetadataProvider) *Service {
+	return &Service{animeSrv: animeSrv, providers: providers}
+}
+
+func (s *Service) provider(source common.ExternalSource) (metadata.MetadataProvider, error) {
+	for _, p := range s.providers {
+		if source == "" || p.Source() == source {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownSource, source)
+}
+
+// Enrich 获取动漫的候选元数据并与当前数据逐字段比较，只应用 req.Apply 中的字段
+// 应用时同时记录条目在外部数据库中的ID
+func (s *Service) Enrich(ctx context.Context, animeID uint, req Request) (*Result, error) {
+	if err := animesrv.ValidateFields(req.Apply); err != nil {
+		return nil, err
+	}
+	anime, err := s.animeSrv.GetByID(animeID)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := s.provider(req.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Anime: anime, Applied: []string{}, Skipped: []string{}}
+	externalID := strings.TrimSpace(req.ExternalID)
+	if externalID == "" {
+		externalID = linkedExternalID(anime, provider.Source())
+	}
+	if externalID == "" {
+		candidates, err := provider.Search(ctx, anime.Name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrProvider, err)
+		}
+		result.Candidates = candidates
+		best, similarity := bestCandidate(anime, candidates)
+		if best == nil || similarity < minSimilarity {
+			return result, ErrNoMatch
+		}
+		externalID = best.ExternalID
+		result.Similarity = similarity
+	}
+
+	result.Metadata, err = provider.Get(ctx, externalID)
+	if errors.Is(err, metadata.ErrNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrProvider, err)
+	}
+	candidate := toAnime(anime, result.Metadata)
+	result.Diff = diff(anime, candidate)
+	for i := range result.Diff {
+		result.Diff[i].Locked = animesrv.IsLocked(anime, result.Diff[i].Field)
+	}
+	if len(req.Apply) == 0 {
+		return result, nil
+	}
+
+	// 只应用确实有候选值的字段
+	var fields []string
+	for _, d := range result.Diff {
+		if slices.Contains(req.Apply, d.Field) && d.Candidate != nil {
+			fields = append(fields, d.Field)
+		}
+	}
+	var skipped []string
+	err = s.animeSrv.Transaction(func(txSrv *animesrv.Service) error {
+		if _, err := txSrv.AddExternalID(anime.ID, result.Metadata.Source, result.Metadata.ExternalID); err != nil {
+			return err
+		}
+		anime, skipped, err = txSrv.UpdateFields(anime.ID, candidate, fields)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Anime = anime
+	for _, field := range fields {
+		if slices.Contains(skipped, field) {
+			result.Skipped = append(result.Skipped, field)
+		} else {
+			result.Applied = append(result.Applied, field)
+		}
+	}
+	return result, nil
+}
+
+// linkedExternalID 返回动漫已关联的外部数据库ID
+func linkedExternalID(anime *models.Anime, source common.ExternalSource) string {
+	for _, ext := range anime.ExternalIDs {
+		if ext.Source == source {
+			return ext.ExternalID
+		}
+	}
+	return ""
+}
+
+// bestCandidate 返回名称与动漫名称或别名最相似的候选条目
+func bestCandidate(anime *models.Anime, candidates []metadata.Metadata) (*metadata.Metadata, float64) {
+	names := append([]string{anime.Name}, common.SplitNames(anime.Aliases)...)
+	var best *metadata.Metadata
+	var bestScore float64
+	for i, c := range candidates {
+		for _, name := range names {
+			for _, candidateName := range append([]string{c.Name}, c.Aliases...) {
+				score := common.NameSimilarity(name, candidateName)
+				if c.Season != "" && c.Season == anime.Season {
+					score = min(score+0.1, 1)
+				}
+				if score > bestScore {
+					best, bestScore = &candidates[i], score
+				}
+			}
+		}
+	}
+	return best, bestScore
+}
+
+// toAnime 将元数据转换为候选的动漫字段，别名与当前别名合并
+func toAnime(anime *models.Anime, m *metadata.Metadata) *models.Anime {
+	aliases := common.SplitNames(anime.Aliases)
+	for _, alias := range m.Aliases {
+		if alias != m.Name && !slices.Contains(aliases, alias) {
+			aliases = append(aliases, alias)
+		}
+	}
+	if m.Name != anime.Name && anime.Name != "" && !slices.Contains(aliases, anime.Name) {
+		aliases = append(aliases, anime.Name)
+	}
+	return &models.Anime{
+		Name:       m.Name,
+		Aliases:    strings.Join(aliases, ","),
+		Production: m.Production,
+		Season:     m.Season,
+		Episodes:   m.Episodes,
+		Image:      m.Image,
+		MediaType:  m.MediaType,
+	}
+}
+
+// diff 逐字段比较，数据源没有提供的字段候选值为 nil
+func diff(current, candidate *models.Anime) []FieldDiff {
+	field := func(name string, cur, cand any, known bool) FieldDiff {
+		d := FieldDiff{Field: name, Current: cur}
+		if known {
+			d.Candidate = cand
+			d.Changed = cur != cand
+		}
+		return d
+	}
+	return []FieldDiff{
+		field(animesrv.FieldName, current.Name, candidate.Name, candidate.Name != ""),
+		field(animesrv.FieldAliases, current.Aliases, candidate.Aliases, candidate.Aliases != ""),
+		field(animesrv.FieldProduction, current.Production, candidate.Production, candidate.Production != ""),
+		field(animesrv.FieldSeason, current.Season, candidate.Season, candidate.Season != ""),
+		field(animesrv.FieldEpisodes, current.Episodes, candidate.Episodes, candidate.Episodes > 0),
+		field(animesrv.FieldImage, current.Image, candidate.Image, candidate.Image != ""),
+		field(animesrv.FieldMediaType, current.MediaType, candidate.MediaType, candidate.MediaType.IsValid()),
+	}
+}
@@ -0,0 +1,176 @@
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao"
+	"kong-anime-go/internal/dao/models"
+	"kong-anime-go/internal/metadata/bangumi"
+	animesrv "kong-anime-go/internal/services/anime"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// frieren 替身服务器中的条目
+var frieren = bangumi.Subject{
+	ID:            400602,
+	Type:          2,
+	Name:          "葬送のフリーレン",
+	NameCN:        "葬送的芙莉莲",
+	Date:          "2023-09-29",
+	Platform:      "TV",
+	Eps:           28,
+	TotalEpisodes: 28,
+	Images:        bangumi.Images{Large: "https://lain.bgm.tv/pic/cover/l/13/c5/400602_ZI8Y9.jpg"},
+	Infobox: []bangumi.InfoboxRow{
+		{Key: "中文名", Value: json.RawMessage(`"葬送的芙莉莲"`)},
+		{Key: "别名", Value: json.RawMessage(`[{"v": "Sousou no Frieren"}]`)},
+		{Key: "动画制作", Value: json.RawMessage(`"MADHOUSE"`)},
+	},
+}
+
+// newTestService 创建使用内存数据库和 Bangumi 替身服务器的补全服务
+func newTestService(t *testing.T) (*Service, *animesrv.Service) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 每个连接都是独立的内存数据库，只保留一个连接
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := dao.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(bangumi.NewStubServer([]bangumi.Subject{frieren}))
+	t.Cleanup(server.Close)
+
+	animeDAO := dao.NewAnimeDAO(db)
+	animeSrv := animesrv.NewService(animeDAO, dao.NewCategoryDAO(db), dao.NewTagDAO(db), dao.NewFollowDAO(db), dao.NewStudioDAO(db))
+	client := bangumi.NewClient(server.URL, "kong-anime-go-test", 5*time.Second)
+	return NewService(animeSrv, client), animeSrv
+}
+
+// createAnime 创建待补全的动漫并锁定 locked 中的字段
+func createAnime(t *testing.T, animeSrv *animesrv.Service, locked ...string) *models.Anime {
+	t.Helper()
+	anime, err := animeSrv.CreateStub(&models.Anime{Name: "芙莉莲", MediaType: common.MediaTypeTV, Episodes: 12})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locked) > 0 {
+		if anime, err = animeSrv.SetLockedFields(anime.ID, locked); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return anime
+}
+
+func TestEnrichPreviewDoesNotWrite(t *testing.T) {
+	srv, animeSrv := newTestService(t)
+	anime := createAnime(t, animeSrv, animesrv.FieldEpisodes)
+
+	result, err := srv.Enrich(context.Background(), anime.ID, Request{ExternalID: "400602"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Applied) != 0 || len(result.Skipped) != 0 {
+		t.Errorf("preview applied %v, skipped %v", result.Applied, result.Skipped)
+	}
+	for _, d := range result.Diff {
+		if d.Locked != (d.Field == animesrv.FieldEpisodes) {
+			t.Errorf("diff %s locked = %v", d.Field, d.Locked)
+		}
+	}
+
+	stored, err := animeSrv.GetByID(anime.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Name != "芙莉莲" || stored.Episodes != 12 || len(stored.ExternalIDs) != 0 {
+		t.Errorf("preview modified anime: name %q, episodes %d, external ids %v", stored.Name, stored.Episodes, stored.ExternalIDs)
+	}
+}
+
+func TestEnrichApply(t *testing.T) {
+	tests := []struct {
+		name        string
+		locked      []string
+		apply       []string
+		wantApplied []string
+		wantSkipped []string
+		wantName    string
+		wantEps     int
+		wantProd    string
+	}{
+		{
+			name:        "partial apply leaves other fields",
+			apply:       []string{animesrv.FieldEpisodes},
+			wantApplied: []string{animesrv.FieldEpisodes},
+			wantSkipped: []string{},
+			wantName:    "芙莉莲",
+			wantEps:     28,
+		},
+		{
+			name:        "locked fields are skipped",
+			locked:      []string{animesrv.FieldName, animesrv.FieldEpisodes},
+			apply:       []string{animesrv.FieldName, animesrv.FieldEpisodes, animesrv.FieldProduction},
+			wantApplied: []string{animesrv.FieldProduction},
+			wantSkipped: []string{animesrv.FieldName, animesrv.FieldEpisodes},
+			wantName:    "芙莉莲",
+			wantEps:     12,
+			wantProd:    "MADHOUSE",
+		},
+		{
+			name:        "all applied fields locked",
+			locked:      []string{animesrv.FieldName},
+			apply:       []string{animesrv.FieldName},
+			wantApplied: []string{},
+			wantSkipped: []string{animesrv.FieldName},
+			wantName:    "芙莉莲",
+			wantEps:     12,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, animeSrv := newTestService(t)
+			anime := createAnime(t, animeSrv, tt.locked...)
+
+			result, err := srv.Enrich(context.Background(), anime.ID, Request{ExternalID: "400602", Apply: tt.apply})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(result.Applied, tt.wantApplied) {
+				t.Errorf("applied = %v, want %v", result.Applied, tt.wantApplied)
+			}
+			if !slices.Equal(result.Skipped, tt.wantSkipped) {
+				t.Errorf("skipped = %v, want %v", result.Skipped, tt.wantSkipped)
+			}
+
+			stored, err := animeSrv.GetByID(anime.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.Name != tt.wantName || stored.Episodes != tt.wantEps || stored.Production != tt.wantProd {
+				t.Errorf("stored name %q, episodes %d, production %q; want %q, %d, %q",
+					stored.Name, stored.Episodes, stored.Production, tt.wantName, tt.wantEps, tt.wantProd)
+			}
+			if len(stored.ExternalIDs) != 1 || stored.ExternalIDs[0].ExternalID != "400602" {
+				t.Errorf("external ids = %v, want bangumi:400602", stored.ExternalIDs)
+			}
+		})
+	}
+}
@@ -0,0 +1,332 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao"
+	"kong-anime-go/internal/dao/models"
+)
+
+// 导出格式
+const (
+	FormatMAL  = "mal"
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// batchSize 每批从数据库读取的追番数量
+const batchSize = 100
+
+// Entry 一条导出的追番记录
+type Entry struct {
+	FollowID      uint                  `json:"follow_id"`
+	AnimeID       uint                  `json:"anime_id"`
+	Name          string                `json:"name"`
+	Aliases       []string              `json:"aliases"`
+	Season        string                `json:"season"`
+	MediaType     string                `json:"media_type"`
+	Episodes      int                   `json:"episodes"`
+	Categories    []string              `json:"categories"`
+	Tags          []string              `json:"tags"`
+	Category      common.FollowCategory `json:"follow_category"`
+	CategoryLabel string                `json:"follow_category_label"`
+	Status        string                `json:"status"`
+	Score         *float64              `json:"score"`
+	FinishedAt    *time.Time            `json:"finished_at"`
+	MALID         string                `json:"mal_id,omitempty"`
+}
+
+// Service 处理导出相关的服务
+type Service struct {
+	followDAO   *dao.FollowDAO
+	categoryDAO *dao.FollowCategoryDAO
+}
+
+// NewService 创建一个新的 ExportService
+func NewService(followDAO *dao.FollowDAO, categoryDAO *dao.FollowCategoryDAO) *Service {
+	return &Service{followDAO: followDAO, categoryDAO: categoryDAO}
+}
+
+// ContentType 返回导出格式对应的 Content-Type 和文件扩展名
+func ContentType(format string) (string, string, error) {
+	switch format {
+	case FormatMAL:
+		return "application/xml; charset=utf-8", "xml", nil
+	case FormatCSV:
+		return "text/csv; charset=utf-8", "csv", nil
+	case FormatJSON:
+		return "application/json; charset=utf-8", "json", nil
+	default:
+		return "", "", errors.New("invalid export format")
+	}
+}
+
+// ExportFollows 将用户的所有追番按指定格式流式写入 w
+func (s *Service) ExportFollows(w io.Writer, userID uint, format string) error {
+	var enc entryEncoder
+	switch format {
+	case FormatMAL:
+		enc = newMALEncoder(w)
+	case FormatCSV:
+		enc = newCSVEncoder(w)
+	case FormatJSON:
+		enc = newJSONEncoder(w)
+	default:
+		return errors.New("invalid export format")
+	}
+
+	// 追番分类的名称从分类表读取，已删除的分类没有名称
+	categories, err := s.categoryDAO.GetAll()
+	if err != nil {
+		return err
+	}
+	labels := make(map[common.FollowCategory]string, len(categories))
+	for _, category := range categories {
+		labels[category.Value] = category.Name
+	}
+
+	if err := enc.begin(); err != nil {
+		return err
+	}
+	err = s.followDAO.FindInBatches(userID, batchSize, func(follows []models.Follow) error {
+		for i := range follows {
+			if err := enc.encode(newEntry(&follows[i], labels)); err != nil {
+				return err
+			}
+		}
+		return enc.flush()
+	})
+	if err != nil {
+		return err
+	}
+	return enc.end()
+}
+
+func newEntry(follow *models.Follow, labels map[common.FollowCategory]string) Entry {
+	anime := follow.Anime
+	entry := Entry{
+		FollowID:      follow.ID,
+		AnimeID:       anime.ID,
+		Name:          anime.Name,
+		Aliases:       common.SplitNames(anime.Aliases),
+		Season:        anime.Season,
+		MediaType:     anime.MediaType.String(),
+		Episodes:      anime.Episodes,
+		Categories:    []string{},
+		Tags:          []string{},
+		Category:      follow.Category,
+		CategoryLabel: labels[follow.Category],
+		Status:        follow.Status.String(),
+		Score:         follow.Score,
+		FinishedAt:    follow.FinishedAt,
+	}
+	for _, category := range anime.Categories {
+		entry.Categories = append(entry.Categories, category.Name)
+	}
+	for _, tag := range anime.Tags {
+		entry.Tags = append(entry.Tags, tag.Name)
+	}
+	for _, ext := range anime.ExternalIDs {
+		if ext.Source == common.ExternalSourceMyAnimeList {
+			entry.MALID = ext.ExternalID
+			break
+		}
+	}
+	return entry
+}
+
+// entryEncoder 按格式写出导出记录
+type entryEncoder interface {
+	begin() error
+	encode(entry Entry) error
+	flush() error
+	end() error
+}
+
+// csvEncoder 导出为 CSV，多值字段用逗号连接
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func newCSVEncoder(w io.Writer) *csvEncoder {
+	return &csvEncoder{w: csv.NewWriter(w)}
+}
+
+func (e *csvEncoder) begin() error {
+	return e.w.Write([]string{
+		"follow_id", "anime_id", "name", "aliases", "season", "media_type", "episodes",
+		"categories", "tags", "follow_category", "follow_category_label", "status", "score", "finished_at", "mal_id",
+	})
+}
+
+func (e *csvEncoder) encode(entry Entry) error {
+	score, finishedAt := "", ""
+	if entry.Score != nil {
+		score = strconv.FormatFloat(*entry.Score, 'f', -1, 64)
+	}
+	if entry.FinishedAt != nil {
+		finishedAt = entry.FinishedAt.Format(time.DateOnly)
+	}
+	return e.w.Write([]string{
+		strconv.FormatUint(uint64(entry.FollowID), 10),
+		strconv.FormatUint(uint64(entry.AnimeID), 10),
+		entry.Name,
+		strings.Join(entry.Aliases, ","),
+		entry.Season,
+		entry.MediaType,
+		strconv.Itoa(entry.Episodes),
+		strings.Join(entry.Categories, ","),
+		strings.Join(entry.Tags, ","),
+		strconv.Itoa(int(entry.Category)),
+		entry.CategoryLabel,
+		entry.Status,
+		score,
+		finishedAt,
+		entry.MALID,
+	})
+}
+
+func (e *csvEncoder) flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvEncoder) end() error {
+	return e.flush()
+}
+
+// jsonEncoder 导出为 JSON 数组，逐条写出
+type jsonEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func newJSONEncoder(w io.Writer) *jsonEncoder {
+	return &jsonEncoder{w: w}
+}
+
+func (e *jsonEncoder) begin() error {
+	_, err := io.WriteString(e.w, "[\n")
+	return err
+}
+
+func (e *jsonEncoder) encode(entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if e.count > 0 {
+		if _, err := io.WriteString(e.w, ",\n"); err != nil {
+			return err
+		}
+	}
+	e.count++
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonEncoder) flush() error {
+	return nil
+}
+
+func (e *jsonEncoder) end() error {
+	_, err := io.WriteString(e.w, "\n]\n")
+	return err
+}
+
+// malStatuses 追番状态到 MyAnimeList 状态的映射
+var malStatuses = map[string]string{
+	common.FollowStatusWantToWatch.String(): "Plan to Watch",
+	common.FollowStatusWatching.String():    "Watching",
+	common.FollowStatusWatched.String():     "Completed",
+	common.FollowStatusOnHold.String():      "On-Hold",
+	common.FollowStatusDropped.String():     "Dropped",
+	common.FollowStatusRewatching.String():  "Completed", // MyAnimeList 中重温是已看完的条目加上 my_rewatching 标记
+}
+
+// malAnime animelist.xml 中的一条动漫记录
+type malAnime struct {
+	XMLName         xml.Name `xml:"anime"`
+	SeriesID        string   `xml:"series_animedb_id"`
+	Title           string   `xml:"series_title"`
+	Type            string   `xml:"series_type"`
+	Episodes        int      `xml:"series_episodes"`
+	WatchedEpisodes int      `xml:"my_watched_episodes"`
+	StartDate       string   `xml:"my_start_date"`
+	FinishDate      string   `xml:"my_finish_date"`
+	Score           int      `xml:"my_score"`
+	Status          string   `xml:"my_status"`
+	Rewatching      int      `xml:"my_rewatching"`
+	Tags            string   `xml:"my_tags"`
+	Comments        string   `xml:"my_comments"`
+	UpdateOnImport  int      `xml:"update_on_import"`
+}
+
+// malEncoder 导出为 MyAnimeList 的 animelist.xml 格式
+type malEncoder struct {
+	w   io.Writer
+	enc *xml.Encoder
+}
+
+func newMALEncoder(w io.Writer) *malEncoder {
+	enc := xml.NewEncoder(w)
+	enc.Indent("\t", "\t")
+	return &malEncoder{w: w, enc: enc}
+}
+
+func (e *malEncoder) begin() error {
+	_, err := io.WriteString(e.w, xml.Header+"<myanimelist>\n\t<myinfo>\n\t\t<user_export_type>1</user_export_type>\n\t</myinfo>\n")
+	return err
+}
+
+func (e *malEncoder) encode(entry Entry) error {
+	anime := malAnime{
+		SeriesID:       entry.MALID,
+		Title:          entry.Name,
+		Type:           strings.ToUpper(entry.MediaType),
+		Episodes:       entry.Episodes,
+		StartDate:      "0000-00-00",
+		FinishDate:     "0000-00-00",
+		Status:         malStatuses[entry.Status],
+		Tags:           strings.Join(append(append([]string{entry.CategoryLabel}, entry.Categories...), entry.Tags...), ","),
+		Comments:       strings.Join(entry.Aliases, ","),
+		UpdateOnImport: 1,
+	}
+	if entry.MediaType == common.MediaTypeSpecial.String() {
+		anime.Type = "Special"
+	}
+	switch entry.Status {
+	case common.FollowStatusWatched.String():
+		anime.WatchedEpisodes = entry.Episodes
+	case common.FollowStatusRewatching.String():
+		anime.WatchedEpisodes = entry.Episodes
+		anime.Rewatching = 1
+	}
+	if entry.Score != nil {
+		anime.Score = int(*entry.Score + 0.5)
+	}
+	if entry.FinishedAt != nil {
+		anime.FinishDate = entry.FinishedAt.Format(time.DateOnly)
+	}
+	return e.enc.Encode(anime)
+}
+
+func (e *malEncoder) flush() error {
+	return e.enc.Flush()
+}
+
+func (e *malEncoder) end() error {
+	if err := e.enc.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(e.w, "\n</myanimelist>\n")
+	return err
+}
@@ -0,0 +1,155 @@
+package follow
+
+import (
+	"errors"
+	"fmt"
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao"
+	"kong-anime-go/internal/dao/models"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrUnknownCategory 追番分类不存在
+	ErrUnknownCategory = errors.New("unknown follow category")
+	// ErrEmptyCategoryName 追番分类名称为空
+	ErrEmptyCategoryName = errors.New("follow category name cannot be empty")
+	// ErrCategoryNameExists 已存在同名的追番分类
+	ErrCategoryNameExists = errors.New("follow category name already exists")
+	// ErrCategoryInUse 仍有追番使用该分类，不能删除
+	ErrCategoryInUse = errors.New("follow category is still used by follows")
+	// ErrCategoryUsedByRule 自动归类规则使用该分类，不能删除
+	ErrCategoryUsedByRule = errors.New("follow category is used by a recategorize rule")
+	// ErrInvalidColor 颜色格式不合法
+	ErrInvalidColor = errors.New("color must be in #RGB or #RRGGBB format")
+	// ErrInvalidOrder 排序列表中有不存在或重复的分类
+	ErrInvalidOrder = errors.New("order must list existing follow categories without duplicates")
+)
+
+// ValidateCategory 检查追番分类是否存在
+func (s *Service) ValidateCategory(value common.FollowCategory) error {
+	_, err := s.categoryDAO.GetByValue(value)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: %d", ErrUnknownCategory, value)
+	}
+	return err
+}
+
+// GetCategories 按显示顺序获取所有追番分类
+func (s *Service) GetCategories() ([]models.FollowCategory, error) {
+	return s.categoryDAO.GetAll()
+}
+
+// CreateCategory 创建一个新的追番分类，分类值自动分配
+func (s *Service) CreateCategory(category *models.FollowCategory) (*models.FollowCategory, error) {
+	category.Name = strings.TrimSpace(category.Name)
+	if err := s.validateCategory(category); err != nil {
+		return nil, err
+	}
+	err := s.categoryDAO.Transaction(func(txDAO *dao.FollowCategoryDAO) error {
+		value, err := txDAO.NextValue()
+		if err != nil {
+			return err
+		}
+		category.Value = value
+		return txDAO.Create(category)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// UpdateCategory 更新追番分类的名称、描述和颜色，分类值不可修改
+func (s *Service) UpdateCategory(category *models.FollowCategory) (*models.FollowCategory, error) {
+	existing, err := s.categoryDAO.GetByValue(category.Value)
+	if err != nil {
+		return nil, err
+	}
+	category.ID = existing.ID
+	category.Name = strings.TrimSpace(category.Name)
+	if err := s.validateCategory(category); err != nil {
+		return nil, err
+	}
+	existing.Name = category.Name
+	existing.Description = category.Description
+	existing.Color = category.Color
+	if err := s.categoryDAO.Update(existing); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// ReserveCategories 登记自动归类规则使用的追番分类（分类 -> 规则名称），这些分类不能删除
+func (s *Service) ReserveCategories(categories map[common.FollowCategory]string) {
+	s.ruleCategories = categories
+}
+
+// DeleteCategory 删除追番分类，仍有追番或自动归类规则使用该分类时拒绝删除
+func (s *Service) DeleteCategory(value common.FollowCategory) (common.FollowCategory, error) {
+	existing, err := s.categoryDAO.GetByValue(value)
+	if err != nil {
+		return 0, err
+	}
+	if rule, ok := s.ruleCategories[value]; ok {
+		return 0, fmt.Errorf("%w (%s)", ErrCategoryUsedByRule, rule)
+	}
+	count, err := s.categoryDAO.CountFollows(value)
+	if err != nil {
+		return 0, err
+	}
+	if count > 0 {
+		return 0, fmt.Errorf("%w (%d)", ErrCategoryInUse, count)
+	}
+	return value, s.categoryDAO.HardDelete(existing.ID)
+}
+
+// ReorderCategories 按 values 的顺序排列追番分类，未列出的分类保持原有顺序排在后面
+func (s *Service) ReorderCategories(values []common.FollowCategory) ([]models.FollowCategory, error) {
+	categories, err := s.categoryDAO.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	listed := make(map[common.FollowCategory]bool, len(values))
+	for _, value := range values {
+		exists := slices.ContainsFunc(categories, func(c models.FollowCategory) bool { return c.Value == value })
+		if listed[value] || !exists {
+			return nil, fmt.Errorf("%w: %d", ErrInvalidOrder, value)
+		}
+		listed[value] = true
+	}
+	order := append([]common.FollowCategory{}, values...)
+	for _, category := range categories {
+		if !listed[category.Value] {
+			order = append(order, category.Value)
+		}
+	}
+	err = s.categoryDAO.Transaction(func(txDAO *dao.FollowCategoryDAO) error {
+		return txDAO.SetSortOrders(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.categoryDAO.GetAll()
+}
+
+// validateCategory 检查追番分类的名称和颜色，category.ID 为 0 时表示新建
+func (s *Service) validateCategory(category *models.FollowCategory) error {
+	if category.Name == "" {
+		return ErrEmptyCategoryName
+	}
+	if category.Color != "" && !common.IsHexColor(category.Color) {
+		return ErrInvalidColor
+	}
+	other, err := s.categoryDAO.GetByName(category.Name)
+	if err == nil && other.ID != category.ID {
+		return fmt.Errorf("%w: %q", ErrCategoryNameExists, category.Name)
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
@@ -2,27 +2,35 @@ package follow
 
 import (
 	"errors"
+	"kong-anime-go/internal/common"
 	"kong-anime-go/internal/dao"
 	"kong-anime-go/internal/dao/models"
+	animesrv "kong-anime-go/internal/services/anime"
+	"time"
 )
 
 // Service 处理追番相关的服务
 type Service struct {
-	followDAO *dao.FollowDAO
-	animeDAO  *dao.AnimeDAO
+	followDAO   *dao.FollowDAO
+	animeDAO    *dao.AnimeDAO
+	categoryDAO *dao.FollowCategoryDAO
+
+	ruleCategories map[common.FollowCategory]string // 自动归类规则使用的分类 -> 规则名称
 }
 
 // NewService 创建一个新的 FollowService
-func NewService(followDAO *dao.FollowDAO, animeDAO *dao.AnimeDAO) *Service {
+func NewService(followDAO *dao.FollowDAO, animeDAO *dao.AnimeDAO, categoryDAO *dao.FollowCategoryDAO) *Service {
 	return &Service{
-		followDAO: followDAO,
-		animeDAO:  animeDAO,
+		followDAO:   followDAO,
+		animeDAO:    animeDAO,
+		categoryDAO: categoryDAO,
 	}
 }
 
-// Create 创建一个新的追番
-func (s *Service) Create(follow *models.Follow) (*models.Follow, error) {
-	existingFollow, err := s.followDAO.GetByAnimeID(follow.AnimeID)
+// Create 为用户创建一个新的追番
+func (s *Service) Create(userID uint, follow *models.Follow) (*models.Follow, error) {
+	follow.UserID = userID
+	existingFollow, err := s.followDAO.GetByAnimeID(userID, follow.AnimeID)
 	if err != nil {
 		return nil, err
 	}
@@ -36,20 +44,26 @@ func (s *Service) Create(follow *models.Follow) (*models.Follow, error) {
 	if anime == nil {
 		return nil, errors.New("anime not found")
 	}
+	// 初始状态与状态变更的时间处理相同，导入时已知的看完时间（如 MAL 的完成日期）优先于当前时间
+	finishedAt := follow.FinishedAt
+	applyStatus(follow, follow.Status, time.Now())
+	if finishedAt != nil && follow.FinishedAt != nil {
+		follow.FinishedAt = finishedAt
+	}
 	if err := s.followDAO.Create(follow); err != nil {
 		return nil, err
 	}
-	return s.followDAO.GetByID(follow.ID)
+	return withAiringStatus(s.followDAO.GetByID(userID, follow.ID))
 }
 
-// Delete 删除一个追番
-func (s *Service) Delete(id uint) (uint, error) {
-	return id, s.followDAO.Delete(id)
+// Delete 删除用户的一个追番
+func (s *Service) Delete(userID, id uint) (uint, error) {
+	return id, s.followDAO.Delete(userID, id)
 }
 
-// Update 更新一个追番
-func (s *Service) Update(follow *models.Follow) (*models.Follow, error) {
-	existingFollow, err := s.followDAO.GetByID(follow.ID)
+// Update 更新用户的一个追番，状态变化时按状态机处理（与 UpdateStatus 相同），看完时间和弃番时间由状态变更维护，忽略请求中的值
+func (s *Service) Update(userID uint, follow *models.Follow) (*models.Follow, error) {
+	existingFollow, err := s.followDAO.GetByID(userID, follow.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -59,27 +73,29 @@ func (s *Service) Update(follow *models.Follow) (*models.Follow, error) {
 	if existingFollow.AnimeID != follow.AnimeID {
 		return nil, errors.New("cannot change AnimeID")
 	}
+	if err := transition(existingFollow, follow.Status, time.Now()); err != nil {
+		return nil, err
+	}
 	existingFollow.Category = follow.Category
-	existingFollow.Status = follow.Status
-	existingFollow.FinishedAt = follow.FinishedAt
+	existingFollow.Score = follow.Score
 	if err := s.followDAO.Update(existingFollow); err != nil {
 		return nil, err
 	}
-	return s.followDAO.GetByID(follow.ID)
+	return withAiringStatus(s.followDAO.GetByID(userID, follow.ID))
 }
 
-// GetByID 根据ID获取追番
-func (s *Service) GetByID(id uint) (*models.Follow, error) {
-	return s.followDAO.GetByID(id)
+// GetByID 根据ID获取用户的追番
+func (s *Service) GetByID(userID, id uint) (*models.Follow, error) {
+	return withAiringStatus(s.followDAO.GetByID(userID, id))
 }
 
-// GetByAnimeID 根据AnimeID获取追番
-func (s *Service) GetByAnimeID(animeID uint) (*models.Follow, error) {
-	return s.followDAO.GetByAnimeID(animeID)
+// GetByAnimeID 根据AnimeID获取用户的追番
+func (s *Service) GetByAnimeID(userID, animeID uint) (*models.Follow, error) {
+	return withAiringStatus(s.followDAO.GetByAnimeID(userID, animeID))
 }
 
-// GetAll 获取所有追番
-func (s *Service) GetAll(page, pageSize int, category *int, status *int, name *string, sorter *string) ([]models.Follow, int64, error) {
+// GetAll 获取用户的所有追番
+func (s *Service) GetAll(userID uint, page, pageSize int, category *int, status *int, name *string, sorter *string) ([]models.Follow, int64, error) {
 	var categoryInt, statusInt int
 	if category != nil {
 		categoryInt = *category
@@ -91,5 +107,21 @@ func (s *Service) GetAll(page, pageSize int, category *int, status *int, name *s
 	} else {
 		statusInt = -1
 	}
-	return s.followDAO.GetAllPaginated(page, pageSize, categoryInt, statusInt, name, sorter)
+	follows, total, err := s.followDAO.GetAllPaginated(userID, page, pageSize, categoryInt, statusInt, name, sorter)
+	if err != nil {
+		return nil, 0, err
+	}
+	now := time.Now()
+	for i := range follows {
+		follows[i].Anime.AiringStatus = animesrv.EffectiveAiringStatus(&follows[i].Anime, now)
+	}
+	return follows, total, nil
+}
+
+// withAiringStatus 按当前时间推导追番中动漫的放送状态，数据库中自动推导的状态只在读取动漫时定期刷新
+func withAiringStatus(follow *models.Follow, err error) (*models.Follow, error) {
+	if follow != nil {
+		follow.Anime.AiringStatus = animesrv.EffectiveAiringStatus(&follow.Anime, time.Now())
+	}
+	return follow, err
 }
@@ -0,0 +1,83 @@
+package follow
+
+import (
+	"errors"
+	"fmt"
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao/models"
+	"kong-anime-go/internal/i18n"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrInvalidTransition 当前状态不能变更为目标状态
+var ErrInvalidTransition = errors.New("invalid follow status transition")
+
+// transitions 追番状态允许的变更，相同状态之间的变更视为无操作
+var transitions = map[common.FollowStatus][]common.FollowStatus{
+	common.FollowStatusWantToWatch: {common.FollowStatusWatching, common.FollowStatusDropped},
+	common.FollowStatusWatching:    {common.FollowStatusWatched, common.FollowStatusOnHold, common.FollowStatusDropped},
+	common.FollowStatusOnHold:      {common.FollowStatusWatching, common.FollowStatusWatched, common.FollowStatusDropped},
+	common.FollowStatusDropped:     {common.FollowStatusWantToWatch, common.FollowStatusWatching},
+	common.FollowStatusWatched:     {common.FollowStatusRewatching},
+	common.FollowStatusRewatching:  {common.FollowStatusWatched, common.FollowStatusOnHold, common.FollowStatusDropped},
+}
+
+// AllowedTransitions 返回从 from 可以变更到的状态
+func AllowedTransitions(from common.FollowStatus) []common.FollowStatus {
+	return transitions[from]
+}
+
+// transition 按状态机将追番变更为 to 状态，并由 applyStatus 处理时间的变化
+func transition(follow *models.Follow, to common.FollowStatus, now time.Time) error {
+	from := follow.Status
+	if from == to {
+		return nil
+	}
+	allowed := AllowedTransitions(from)
+	if !slices.Contains(allowed, to) {
+		names := make([]string, len(allowed))
+		for i, status := range allowed {
+			names[i] = status.String()
+		}
+		return fmt.Errorf("%w: %w", ErrInvalidTransition,
+			i18n.Errorf("cannot change from %s to %s (allowed: %s)", from, to, strings.Join(names, ", ")))
+	}
+	applyStatus(follow, to, now)
+	return nil
+}
+
+// applyStatus 将追番设为 status 状态并处理时间的变化，创建追番时同样使用：
+// 看完时记录看完时间，弃番时记录弃番时间，重温时保留之前的看完时间，其他状态清除两者
+func applyStatus(follow *models.Follow, status common.FollowStatus, now time.Time) {
+	follow.Status = status
+	switch status {
+	case common.FollowStatusWatched:
+		follow.FinishedAt = &now
+		follow.DroppedAt = nil
+	case common.FollowStatusRewatching:
+		follow.DroppedAt = nil
+	case common.FollowStatusDropped:
+		follow.FinishedAt = nil
+		follow.DroppedAt = &now
+	default:
+		follow.FinishedAt = nil
+		follow.DroppedAt = nil
+	}
+}
+
+// UpdateStatus 按状态机变更用户的追番状态，不允许的变更返回 ErrInvalidTransition
+func (s *Service) UpdateStatus(userID, id uint, status common.FollowStatus) (*models.Follow, error) {
+	follow, err := s.followDAO.GetByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := transition(follow, status, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.followDAO.Update(follow); err != nil {
+		return nil, err
+	}
+	return withAiringStatus(s.followDAO.GetByID(userID, id))
+}
@@ -0,0 +1,271 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao/models"
+	animesrv "kong-anime-go/internal/services/anime"
+)
+
+// csvColumns 表头名称（忽略大小写）到字段的映射
+var csvColumns = map[string]string{
+	"name":       "name",
+	"名称":         "name",
+	"aliases":    "aliases",
+	"别名":         "aliases",
+	"production": "production",
+	"制作公司":       "production",
+	"season":     "season",
+	"季度":         "season",
+	"episodes":   "episodes",
+	"集数":         "episodes",
+	"categories": "categories",
+	"分类":         "categories",
+	"tags":       "tags",
+	"标签":         "tags",
+	"media_type": "media_type",
+	"类型":         "media_type",
+}
+
+// cellSeparators 单元格中多个值的分隔符
+const cellSeparators = ",，、;；|"
+
+// catalogueRow CSV 中一行待导入的动漫
+type catalogueRow struct {
+	Line       int
+	Anime      *models.Anime
+	Name       string
+	Categories []string
+	Tags       []string
+	Errors     []string // 校验或导入失败的原因
+}
+
+// parseCatalogue 解析动漫目录 CSV/TSV 并逐行校验，返回每一行的解析结果
+// 第一行为表头，name 和 season 列必填
+func parseCatalogue(r io.Reader, delimiter rune) ([]catalogueRow, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if field, ok := csvColumns[name]; ok {
+			columns[field] = i
+		}
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, errors.New("missing name column")
+	}
+	if _, ok := columns["season"]; !ok {
+		return nil, errors.New("missing season column")
+	}
+
+	var rows []catalogueRow
+	seen := make(map[string]int)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		cell := func(field string) string {
+			i, ok := columns[field]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		row := parseCatalogueRecord(line, cell)
+		if row.Anime.Name != "" && row.Anime.Season != "" {
+			key := common.NormalizeName(row.Anime.Name) + "@" + row.Anime.Season
+			if first, ok := seen[key]; ok {
+				row.Errors = append(row.Errors, fmt.Sprintf("duplicate of line %d", first))
+			} else {
+				seen[key] = line
+			}
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("no rows to import")
+	}
+	return rows, nil
+}
+
+func parseCatalogueRecord(line int, cell func(field string) string) catalogueRow {
+	anime := &models.Anime{
+		Name:       cell("name"),
+		Aliases:    strings.Join(splitCell(cell("aliases")), ","),
+		Production: cell("production"),
+	}
+	row := catalogueRow{
+		Line:       line,
+		Anime:      anime,
+		Name:       anime.Name,
+		Categories: splitCell(cell("categories")),
+		Tags:       splitCell(cell("tags")),
+	}
+
+	if anime.Name == "" {
+		row.Errors = append(row.Errors, "name is required")
+	}
+	if season := cell("season"); season == "" {
+		row.Errors = append(row.Errors, "season is required")
+	} else if formatted, err := animesrv.FormatSeason(season); err != nil {
+		row.Errors = append(row.Errors, fmt.Sprintf("invalid season %q", season))
+	} else {
+		anime.Season = formatted
+	}
+	if episodes := cell("episodes"); episodes != "" {
+		// 集数未知时留空，填写时必须为正数
+		n, err := strconv.Atoi(episodes)
+		if err != nil || n < 1 {
+			row.Errors = append(row.Errors, fmt.Sprintf("invalid episodes %q", episodes))
+		}
+		anime.Episodes = n
+	}
+	if mediaType := cell("media_type"); mediaType != "" {
+		mt, ok := common.ParseMediaType(mediaType)
+		if !ok {
+			row.Errors = append(row.Errors, fmt.Sprintf("invalid media type %q", mediaType))
+		}
+		anime.MediaType = mt
+	}
+	if anime.MediaType.IsValid() {
+		if err := animesrv.ValidateMediaType(anime.MediaType, anime.Episodes); err != nil {
+			row.Errors = append(row.Errors, err.Error())
+		}
+	}
+	return row
+}
+
+// ImportCatalogue 导入动漫目录 CSV/TSV
+// 任意一行校验失败或 dryRun 为 true 时不写入数据；否则在同一个事务中导入所有行，任意一行失败时全部回滚
+// 与已有动漫可能重复的行视为失败，force 为 true 时跳过重复检查
+func (s *Service) ImportCatalogue(userID uint, fileName string, r io.Reader, delimiter rune, dryRun, force bool) (*models.ImportJob, error) {
+	rows, err := parseCatalogue(r, delimiter)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &models.ImportJob{
+		UserID:   userID,
+		Kind:     "csv",
+		FileName: fileName,
+		DryRun:   dryRun,
+		Total:    len(rows),
+		Status:   JobStatusCompleted,
+	}
+	valid := true
+	for i, row := range rows {
+		// 预览时逐行检查与已有动漫的重复，实际导入时由 Create 检查（也能发现文件内互相重复的行）
+		if dryRun && !force && len(row.Errors) == 0 {
+			duplicates, err := s.animeSrv.FindDuplicates(row.Anime)
+			if err != nil {
+				return nil, err
+			}
+			if len(duplicates) > 0 {
+				rows[i].Errors = append(rows[i].Errors, (&animesrv.ErrPossibleDuplicate{Candidates: duplicates}).Error())
+			}
+		}
+		if len(rows[i].Errors) > 0 {
+			valid = false
+		}
+	}
+
+	results := make([]models.ImportRow, len(rows))
+	if valid && !dryRun {
+		err = s.animeSrv.Transaction(func(txSrv *animesrv.Service) error {
+			for i, row := range rows {
+				anime, err := txSrv.Create(row.Anime, row.Categories, row.Tags, force)
+				if err != nil {
+					rows[i].Errors = append(rows[i].Errors, err.Error())
+					return fmt.Errorf("line %d: %w", row.Line, err)
+				}
+				results[i].AnimeID = &anime.ID
+			}
+			return nil
+		})
+		if err != nil {
+			job.Status = JobStatusFailed
+			job.Error = "transaction rolled back: " + err.Error()
+			for i := range results {
+				results[i].AnimeID = nil
+			}
+		}
+	} else if !valid {
+		job.Status = JobStatusFailed
+		job.Error = "validation failed, nothing imported"
+	}
+
+	for i, row := range rows {
+		results[i].Line = row.Line
+		results[i].Title = row.Name
+		switch {
+		case len(row.Errors) > 0:
+			results[i].Action = ActionFailed
+			results[i].Message = strings.Join(row.Errors, "; ")
+			job.Failed++
+		case job.Status == JobStatusFailed:
+			results[i].Action = ActionSkipped
+			results[i].Message = "not imported"
+			job.Skipped++
+		default:
+			results[i].Action = ActionCreated
+			job.Created++
+		}
+	}
+
+	if err := s.importDAO.CreateJob(job); err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].JobID = job.ID
+	}
+	if err := s.importDAO.CreateRows(results); err != nil {
+		return nil, err
+	}
+	job.Rows = results
+	return job, nil
+}
+
+// splitCell 拆分单元格中的多个值
+func splitCell(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return strings.ContainsRune(cellSeparators, r)
+	})
+	values := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			values = append(values, field)
+		}
+	}
+	return values
+}
+
+func isBlankRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
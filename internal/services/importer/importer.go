@@ -0,0 +1,149 @@
+package importer
+
+import (
+	"fmt"
+	"log"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao"
+	"kong-anime-go/internal/dao/models"
+	animesrv "kong-anime-go/internal/services/anime"
+	followsrv "kong-anime-go/internal/services/follow"
+)
+
+// 导入任务状态
+const (
+	JobStatusPending   = "pending"
+	JobStatusRunning   = "running"
+	JobStatusCompleted = "completed"
+	JobStatusFailed    = "failed"
+)
+
+// 单行处理结果
+const (
+	ActionCreated = "created"
+	ActionMatched = "matched"
+	ActionSkipped = "skipped"
+	ActionFailed  = "failed"
+)
+
+// fuzzyMatchThreshold 名称模糊匹配的最低相似度
+const fuzzyMatchThreshold = 0.85
+
+// Service 处理导入相关的服务
+type Service struct {
+	importDAO *dao.ImportDAO
+	animeDAO  *dao.AnimeDAO
+	animeSrv  *animesrv.Service
+	followSrv *followsrv.Service
+}
+
+// NewService 创建一个新的 ImportService
+func NewService(importDAO *dao.ImportDAO, animeDAO *dao.AnimeDAO, animeSrv *animesrv.Service, followSrv *followsrv.Service) *Service {
+	return &Service{
+		importDAO: importDAO,
+		animeDAO:  animeDAO,
+		animeSrv:  animeSrv,
+		followSrv: followSrv,
+	}
+}
+
+// GetJobs 获取用户发起的导入任务
+func (s *Service) GetJobs(userID uint, page, pageSize int) ([]models.ImportJob, int64, error) {
+	return s.importDAO.GetJobsPaginated(userID, page, pageSize)
+}
+
+// GetJob 根据ID获取用户发起的导入任务（包含单行结果）
+func (s *Service) GetJob(userID, id uint) (*models.ImportJob, error) {
+	return s.importDAO.GetJobByID(userID, id)
+}
+
+// startJob 创建导入任务并在后台执行 run，run 返回每一行的处理结果
+// 返回的是任务创建时的副本，后台执行只修改 job 本身，执行进度和结果通过 GetJob 查询
+func (s *Service) startJob(job *models.ImportJob, run func() []models.ImportRow) (*models.ImportJob, error) {
+	job.Status = JobStatusPending
+	if err := s.importDAO.CreateJob(job); err != nil {
+		return nil, err
+	}
+	created := *job
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				job.Status = JobStatusFailed
+				job.Error = fmt.Sprint(r)
+				if err := s.importDAO.UpdateJob(job); err != nil {
+					log.Printf("import: failed to update job %d: %v", job.ID, err)
+				}
+			}
+		}()
+
+		job.Status = JobStatusRunning
+		if err := s.importDAO.UpdateJob(job); err != nil {
+			log.Printf("import: failed to update job %d: %v", job.ID, err)
+		}
+
+		rows := run()
+		for i := range rows {
+			rows[i].JobID = job.ID
+			switch rows[i].Action {
+			case ActionCreated:
+				job.Created++
+			case ActionMatched:
+				job.Matched++
+			case ActionSkipped:
+				job.Skipped++
+			case ActionFailed:
+				job.Failed++
+			}
+		}
+		job.Status = JobStatusCompleted
+		if err := s.importDAO.CreateRows(rows); err != nil {
+			job.Status = JobStatusFailed
+			job.Error = err.Error()
+		}
+		if err := s.importDAO.UpdateJob(job); err != nil {
+			log.Printf("import: failed to update job %d: %v", job.ID, err)
+		}
+		log.Printf("import: job %d (%s) %s: created %d, matched %d, skipped %d, failed %d",
+			job.ID, job.Kind, job.Status, job.Created, job.Matched, job.Skipped, job.Failed)
+	}()
+
+	return &created, nil
+}
+
+// nameIndex 用于按名称模糊匹配动漫
+type nameIndex struct {
+	animes []models.Anime
+}
+
+func (s *Service) loadNameIndex() (*nameIndex, error) {
+	animes, err := s.animeDAO.GetNameIndex()
+	if err != nil {
+		return nil, err
+	}
+	return &nameIndex{animes: animes}, nil
+}
+
+// add 将新建（或预览中将要新建）的动漫加入索引
+func (idx *nameIndex) add(anime models.Anime) {
+	idx.animes = append(idx.animes, anime)
+}
+
+// match 返回名称或别名与 title 最相似的动漫，相似度低于阈值时返回 nil
+func (idx *nameIndex) match(title string) (*models.Anime, float64) {
+	var best *models.Anime
+	bestScore := 0.0
+	for i := range idx.animes {
+		names := append([]string{idx.animes[i].Name}, common.SplitNames(idx.animes[i].Aliases)...)
+		for _, name := range names {
+			if score := common.NameSimilarity(title, name); score > bestScore {
+				best, bestScore = &idx.animes[i], score
+			}
+		}
+	}
+	if bestScore < fuzzyMatchThreshold {
+		return nil, bestScore
+	}
+	return best, bestScore
+}
@@ -0,0 +1,213 @@
+package importer
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao/models"
+	animesrv "kong-anime-go/internal/services/anime"
+)
+
+// malExport MyAnimeList 导出的 animelist.xml
+type malExport struct {
+	XMLName xml.Name   `xml:"myanimelist"`
+	Animes  []malAnime `xml:"anime"`
+}
+
+// malAnime animelist.xml 中的一条动漫记录
+type malAnime struct {
+	SeriesID   string `xml:"series_animedb_id"`
+	Title      string `xml:"series_title"`
+	Type       string `xml:"series_type"`
+	Episodes   int    `xml:"series_episodes"`
+	Score      int    `xml:"my_score"`
+	Status     string `xml:"my_status"`
+	Rewatching int    `xml:"my_rewatching"`
+	FinishDate string `xml:"my_finish_date"`
+}
+
+// malStatuses MyAnimeList 状态到追番状态的映射，同时兼容文字和数字两种导出格式
+var malStatuses = map[string]common.FollowStatus{
+	"watching":      common.FollowStatusWatching,
+	"1":             common.FollowStatusWatching,
+	"completed":     common.FollowStatusWatched,
+	"2":             common.FollowStatusWatched,
+	"on-hold":       common.FollowStatusOnHold,
+	"3":             common.FollowStatusOnHold,
+	"dropped":       common.FollowStatusDropped,
+	"4":             common.FollowStatusDropped,
+	"plan to watch": common.FollowStatusWantToWatch,
+	"6":             common.FollowStatusWantToWatch,
+}
+
+// malMediaTypes MyAnimeList 类型到动漫类型的映射，电影不在此列
+var malMediaTypes = map[string]common.MediaType{
+	"tv":      common.MediaTypeTV,
+	"ova":     common.MediaTypeOVA,
+	"ona":     common.MediaTypeONA,
+	"special": common.MediaTypeSpecial,
+	"music":   common.MediaTypeSpecial,
+	"unknown": common.MediaTypeTV,
+}
+
+// ParseMAL 解析 MyAnimeList 导出的 animelist.xml
+func ParseMAL(r io.Reader) ([]malAnime, error) {
+	var export malExport
+	if err := xml.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("invalid MAL export: %w", err)
+	}
+	if len(export.Animes) == 0 {
+		return nil, errors.New("MAL export contains no anime")
+	}
+	return export.Animes, nil
+}
+
+// ImportMAL 创建 MyAnimeList 导入任务，追番导入到 userID 对应的用户，dryRun 为 true 时只生成报告不写入数据
+// editCatalogue 为 false 时（没有 editor 角色）只匹配已有的动漫，不新建动漫也不补充 MAL ID，匹配不到的记录跳过
+func (s *Service) ImportMAL(userID uint, fileName string, r io.Reader, category common.FollowCategory, dryRun, editCatalogue bool) (*models.ImportJob, error) {
+	if err := s.followSrv.ValidateCategory(category); err != nil {
+		return nil, err
+	}
+	entries, err := ParseMAL(r)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &models.ImportJob{
+		UserID:   userID,
+		Kind:     "mal",
+		FileName: fileName,
+		DryRun:   dryRun,
+		Total:    len(entries),
+	}
+	return s.startJob(job, func() []models.ImportRow {
+		idx, err := s.loadNameIndex()
+		if err != nil {
+			return []models.ImportRow{{Line: 0, Action: ActionFailed, Message: err.Error()}}
+		}
+		rows := make([]models.ImportRow, 0, len(entries))
+		for i, entry := range entries {
+			row := s.importMALEntry(userID, idx, entry, category, dryRun, editCatalogue)
+			row.Line = i + 1
+			rows = append(rows, row)
+		}
+		return rows
+	})
+}
+
+// importMALEntry 处理一条记录：先按 MAL ID 匹配，再按名称模糊匹配，都匹配不到时新建动漫，最后创建追番
+func (s *Service) importMALEntry(userID uint, idx *nameIndex, entry malAnime, category common.FollowCategory, dryRun, editCatalogue bool) models.ImportRow {
+	row := models.ImportRow{Title: strings.TrimSpace(entry.Title)}
+	if row.Title == "" {
+		return failRow(row, "missing title")
+	}
+
+	status, ok := malStatuses[strings.ToLower(strings.TrimSpace(entry.Status))]
+	if !ok {
+		row.Action = ActionSkipped
+		row.Message = fmt.Sprintf("unsupported status %q", entry.Status)
+		return row
+	}
+	// 重温在 MyAnimeList 中是已看完的条目加上 my_rewatching 标记
+	if status == common.FollowStatusWatched && entry.Rewatching == 1 {
+		status = common.FollowStatusRewatching
+	}
+	mediaType, ok := malMediaTypes[strings.ToLower(strings.TrimSpace(entry.Type))]
+	if !ok {
+		row.Action = ActionSkipped
+		row.Message = fmt.Sprintf("unsupported type %q", entry.Type)
+		return row
+	}
+
+	var anime *models.Anime
+	var err error
+	malID := strings.TrimSpace(entry.SeriesID)
+	if malID != "" {
+		anime, err = s.animeDAO.GetByExternalID(common.ExternalSourceMyAnimeList, malID)
+		if err != nil {
+			return failRow(row, err.Error())
+		}
+	}
+	if anime != nil {
+		row.Action = ActionMatched
+		row.Message = "matched by MAL id"
+	} else if matched, score := idx.match(row.Title); matched != nil {
+		anime = matched
+		row.Action = ActionMatched
+		row.Message = fmt.Sprintf("matched by title %q (similarity %.2f)", matched.Name, score)
+		if !dryRun && editCatalogue && malID != "" && anime.ID != 0 {
+			// 为匹配到的动漫补充 MAL ID，已被其他动漫占用时忽略
+			_, err := s.animeSrv.AddExternalID(anime.ID, common.ExternalSourceMyAnimeList, malID)
+			if err != nil && !errors.Is(err, animesrv.ErrExternalIDTaken) {
+				return failRow(row, err.Error())
+			}
+		}
+	} else if !editCatalogue {
+		row.Action = ActionSkipped
+		row.Message = "anime not in catalogue; creating it requires the editor role"
+		return row
+	} else {
+		anime = &models.Anime{Name: row.Title, MediaType: mediaType, Episodes: entry.Episodes}
+		row.Action = ActionCreated
+		if dryRun {
+			idx.add(*anime)
+		} else {
+			anime, err = s.animeSrv.CreateStub(anime)
+			if err != nil {
+				return failRow(row, err.Error())
+			}
+			idx.add(*anime)
+			if malID != "" {
+				if _, err := s.animeSrv.AddExternalID(anime.ID, common.ExternalSourceMyAnimeList, malID); err != nil {
+					return failRow(row, err.Error())
+				}
+			}
+		}
+	}
+	if anime.ID != 0 {
+		row.AnimeID = &anime.ID
+	}
+
+	follow := &models.Follow{AnimeID: anime.ID, Category: category, Status: status}
+	if entry.Score > 0 {
+		score := float64(entry.Score)
+		follow.Score = &score
+	}
+	if status == common.FollowStatusWatched || status == common.FollowStatusRewatching {
+		if finishedAt, err := time.ParseInLocation(time.DateOnly, entry.FinishDate, time.Local); err == nil {
+			follow.FinishedAt = &finishedAt
+		}
+	}
+
+	if anime.ID != 0 {
+		existingFollow, err := s.followSrv.GetByAnimeID(userID, anime.ID)
+		if err != nil {
+			return failRow(row, err.Error())
+		}
+		if existingFollow != nil {
+			row.Action = ActionSkipped
+			row.Message = strings.TrimPrefix(row.Message+"; follow already exists", "; ")
+			return row
+		}
+	}
+	if dryRun {
+		return row
+	}
+	follow, err = s.followSrv.Create(userID, follow)
+	if err != nil {
+		return failRow(row, err.Error())
+	}
+	row.FollowID = &follow.ID
+	return row
+}
+
+func failRow(row models.ImportRow, message string) models.ImportRow {
+	row.Action = ActionFailed
+	row.Message = message
+	return row
+}
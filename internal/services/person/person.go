@@ -0,0 +1,125 @@
+package person
+
+import (
+	"errors"
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao"
+	"kong-anime-go/internal/dao/models"
+)
+
+// Service 处理职员和声优相关的服务
+type Service struct {
+	personDAO *dao.PersonDAO
+	animeDAO  *dao.AnimeDAO
+	movieDAO  *dao.MovieDAO
+}
+
+// NewService 创建一个新的 PersonService
+func NewService(personDAO *dao.PersonDAO, animeDAO *dao.AnimeDAO, movieDAO *dao.MovieDAO) *Service {
+	return &Service{
+		personDAO: personDAO,
+		animeDAO:  animeDAO,
+		movieDAO:  movieDAO,
+	}
+}
+
+// Create 创建一个新的人物
+func (s *Service) Create(person *models.Person) (*models.Person, error) {
+	if err := s.personDAO.Create(person); err != nil {
+		return nil, err
+	}
+	return person, nil
+}
+
+// Delete 删除一个人物
+func (s *Service) Delete(id uint) (uint, error) {
+	relatedItems, err := s.personDAO.CheckRelatedItems(id)
+	if err != nil {
+		return 0, err
+	}
+	if relatedItems {
+		return 0, errors.New("cannot delete person with related credits")
+	}
+	// 硬删除
+	if err := s.personDAO.HardDelete(id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// Update 更新一个人物
+func (s *Service) Update(person *models.Person) (*models.Person, error) {
+	existingPerson, err := s.personDAO.GetByID(person.ID)
+	if err != nil {
+		return nil, err
+	}
+	existingPerson.Name = person.Name
+	existingPerson.Aliases = person.Aliases
+	if err := s.personDAO.Update(existingPerson); err != nil {
+		return nil, err
+	}
+	return existingPerson, nil
+}
+
+// GetByID 根据ID获取人物
+func (s *Service) GetByID(id uint) (*models.Person, error) {
+	return s.personDAO.GetByID(id)
+}
+
+// GetAll 获取人物列表，name 不为空时按名称和别名模糊查询
+func (s *Service) GetAll(name string, page, pageSize int) ([]models.Person, int64, error) {
+	return s.personDAO.GetAllPaginated(name, page, pageSize)
+}
+
+// GetFilmography 获取人物参与的作品
+func (s *Service) GetFilmography(personID uint, role *common.CreditRole) ([]models.Credit, error) {
+	if _, err := s.personDAO.GetByID(personID); err != nil {
+		return nil, err
+	}
+	return s.personDAO.GetCreditsByPerson(personID, role)
+}
+
+// CreateCredit 为动漫或电影添加职员或声优
+func (s *Service) CreateCredit(credit *models.Credit) (*models.Credit, error) {
+	if !credit.Role.IsValid() {
+		return nil, errors.New("invalid role")
+	}
+	if (credit.AnimeID == nil) == (credit.MovieID == nil) {
+		return nil, errors.New("exactly one of anime_id and movie_id is required")
+	}
+	if credit.Character != "" && credit.Role != common.CreditRoleVoiceActor {
+		return nil, errors.New("character name is only allowed for voice actors")
+	}
+	if _, err := s.personDAO.GetByID(credit.PersonID); err != nil {
+		return nil, errors.New("person not found")
+	}
+	if credit.AnimeID != nil {
+		if _, err := s.animeDAO.GetByID(*credit.AnimeID); err != nil {
+			return nil, errors.New("anime not found")
+		}
+	}
+	if credit.MovieID != nil {
+		if _, err := s.movieDAO.GetByID(*credit.MovieID); err != nil {
+			return nil, errors.New("movie not found")
+		}
+	}
+	if err := s.personDAO.CreateCredit(credit); err != nil {
+		return nil, err
+	}
+	return s.personDAO.GetCreditByID(credit.ID)
+}
+
+// DeleteCredit 删除担当
+func (s *Service) DeleteCredit(id uint) (uint, error) {
+	return id, s.personDAO.HardDeleteCredit(id)
+}
+
+// GetAnimeCredits 获取动漫的职员和声优
+func (s *Service) GetAnimeCredits(animeID uint) ([]models.Credit, error) {
+	return s.personDAO.GetCreditsByAnime(animeID)
+}
+
+// GetWatchedStats 获取在用户看过的追番中出现最多的人物
+func (s *Service) GetWatchedStats(userID uint, role *common.CreditRole, limit int) ([]dao.PersonWatchedCount, error) {
+	return s.personDAO.GetTopInWatchedFollows(userID, role, limit)
+}
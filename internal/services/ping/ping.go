@@ -5,12 +5,27 @@ import (
 	"time"
 )
 
+// 健康状态
+const (
+	HealthOK    = "ok"
+	HealthError = "error"
+)
+
+// HealthChecker 健康检查项
+type HealthChecker interface {
+	HealthName() string
+	// HealthCheck 返回检查详情，不健康时返回错误
+	HealthCheck() (any, error)
+}
+
 // Service 处理 Ping 相关的服务
-type Service struct{}
+type Service struct {
+	checkers []HealthChecker
+}
 
 // NewService 创建一个新的 PingService
-func NewService() *Service {
-	return &Service{}
+func NewService(checkers ...HealthChecker) *Service {
+	return &Service{checkers: checkers}
 }
 
 // GetHello 返回一个问候消息
@@ -25,3 +40,23 @@ func (s *Service) GetPing() (*models.Ping, error) {
 		Time: time.Now().Format(time.DateTime),
 	}, nil
 }
+
+// GetHealth 执行所有健康检查，任意一项失败时整体状态为 error
+func (s *Service) GetHealth() *models.Health {
+	health := &models.Health{
+		Status: HealthOK,
+		Time:   time.Now().Format(time.DateTime),
+		Checks: make(map[string]models.HealthCheck, len(s.checkers)),
+	}
+	for _, checker := range s.checkers {
+		details, err := checker.HealthCheck()
+		check := models.HealthCheck{Status: HealthOK, Details: details}
+		if err != nil {
+			check.Status = HealthError
+			check.Error = err.Error()
+			health.Status = HealthError
+		}
+		health.Checks[checker.HealthName()] = check
+	}
+	return health
+}
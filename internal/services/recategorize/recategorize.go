@@ -0,0 +1,275 @@
+package recategorize
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/config"
+	"kong-anime-go/internal/dao"
+	"kong-anime-go/internal/dao/models"
+	animesrv "kong-anime-go/internal/services/anime"
+
+	"gorm.io/gorm"
+)
+
+// 触发方式
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+)
+
+// Rule 追番自动归类规则
+type Rule struct {
+	Name           string                `json:"name"`
+	From           common.FollowCategory `json:"from"`
+	To             common.FollowCategory `json:"to"`
+	AiringStatuses []common.AiringStatus `json:"airing_statuses"`
+}
+
+// Change 一条追番分类变更
+type Change struct {
+	FollowID     uint                  `json:"follow_id"`
+	UserID       uint                  `json:"user_id"`
+	AnimeID      uint                  `json:"anime_id"`
+	AnimeName    string                `json:"anime_name"`
+	Rule         string                `json:"rule"`
+	FromCategory common.FollowCategory `json:"from_category"`
+	ToCategory   common.FollowCategory `json:"to_category"`
+}
+
+// DefaultRule 默认规则：已完结的新番移动到目标分类
+func DefaultRule(target common.FollowCategory) Rule {
+	return Rule{
+		Name:           "finished_new",
+		From:           common.FollowCategoryNew,
+		To:             target,
+		AiringStatuses: []common.AiringStatus{common.AiringStatusFinished},
+	}
+}
+
+// RulesFromConfig 根据配置生成规则，未配置规则时使用默认规则，validate 检查追番分类是否存在
+func RulesFromConfig(cfg config.RecategorizeConfig, validate func(common.FollowCategory) error) ([]Rule, error) {
+	if len(cfg.Rules) == 0 {
+		target := common.FollowCategory(cfg.DefaultTarget)
+		if err := validate(target); err != nil {
+			return nil, fmt.Errorf("invalid default target category: %w", err)
+		}
+		return []Rule{DefaultRule(target)}, nil
+	}
+
+	rules := make([]Rule, 0, len(cfg.Rules))
+	for i, r := range cfg.Rules {
+		rule := Rule{
+			Name: r.Name,
+			From: common.FollowCategory(r.From),
+			To:   common.FollowCategory(r.To),
+		}
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule_%d", i+1)
+		}
+		if err := validate(rule.From); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+		if err := validate(rule.To); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+		if rule.From == rule.To {
+			return nil, fmt.Errorf("rule %s: from and to are the same category", rule.Name)
+		}
+		for _, name := range r.AiringStatuses {
+			status, ok := common.ParseAiringStatus(name)
+			if !ok {
+				return nil, fmt.Errorf("rule %s: invalid airing status %q", rule.Name, name)
+			}
+			rule.AiringStatuses = append(rule.AiringStatuses, status)
+		}
+		if len(rule.AiringStatuses) == 0 {
+			rule.AiringStatuses = []common.AiringStatus{common.AiringStatusFinished}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// RuleCategories 返回规则使用的追番分类及使用该分类的第一条规则的名称
+func RuleCategories(rules []Rule) map[common.FollowCategory]string {
+	categories := make(map[common.FollowCategory]string)
+	for _, rule := range rules {
+		for _, category := range []common.FollowCategory{rule.From, rule.To} {
+			if _, ok := categories[category]; !ok {
+				categories[category] = rule.Name
+			}
+		}
+	}
+	return categories
+}
+
+// Service 处理追番自动归类的服务
+type Service struct {
+	followDAO       *dao.FollowDAO
+	recategorizeDAO *dao.RecategorizeDAO
+	animeSrv        *animesrv.Service
+	rules           []Rule
+	validate        func(common.FollowCategory) error // 检查追番分类是否存在
+
+	mu sync.Mutex // 避免定时任务与手动执行同时进行
+}
+
+// NewService 创建一个新的 RecategorizeService，validate 检查追番分类是否存在
+func NewService(followDAO *dao.FollowDAO, recategorizeDAO *dao.RecategorizeDAO, animeSrv *animesrv.Service, rules []Rule, validate func(common.FollowCategory) error) *Service {
+	return &Service{
+		followDAO:       followDAO,
+		recategorizeDAO: recategorizeDAO,
+		animeSrv:        animeSrv,
+		rules:           rules,
+		validate:        validate,
+	}
+}
+
+// Rules 返回当前生效的规则
+func (s *Service) Rules() []Rule {
+	return s.rules
+}
+
+// Preview 预览将要发生的变更（不写入数据库），userID 为 nil 时包括所有用户的追番
+func (s *Service) Preview(userID *uint) ([]Change, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.validateRules(); err != nil {
+		return nil, err
+	}
+	return s.collectChanges(userID, time.Now())
+}
+
+// Run 执行一次自动归类并记录日志，userID 为 nil 时处理所有用户的追番
+// 分类变更和执行记录在同一个事务中写入，失败时回滚所有变更，只记录失败原因
+func (s *Service) Run(trigger string, userID *uint) (*models.RecategorizeRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run := &models.RecategorizeRun{UserID: userID, Trigger: trigger}
+	// 追番分类可能在启动后被删除或因恢复备份而变化，每次执行前重新检查规则
+	err := s.validateRules()
+	if err == nil {
+		// 执行前先把推导出的放送状态写入数据库，与归类结果保持一致
+		_, err = s.animeSrv.RefreshAiringStatuses()
+	}
+	var changes []Change
+	if err == nil {
+		changes, err = s.collectChanges(userID, time.Now())
+	}
+	if err == nil {
+		err = s.recategorizeDAO.Transaction(func(tx *gorm.DB) error {
+			followDAO := s.followDAO.WithTx(tx)
+			for _, change := range changes {
+				if err := followDAO.UpdateCategory(change.FollowID, change.ToCategory); err != nil {
+					return err
+				}
+				run.Logs = append(run.Logs, models.RecategorizeLog{
+					FollowID:     change.FollowID,
+					UserID:       change.UserID,
+					AnimeID:      change.AnimeID,
+					AnimeName:    change.AnimeName,
+					Rule:         change.Rule,
+					FromCategory: change.FromCategory,
+					ToCategory:   change.ToCategory,
+				})
+			}
+			run.Changed = len(run.Logs)
+			return s.recategorizeDAO.WithTx(tx).CreateRun(run)
+		})
+	}
+	if err != nil {
+		run = &models.RecategorizeRun{UserID: userID, Trigger: trigger, Error: err.Error()}
+		if createErr := s.recategorizeDAO.CreateRun(run); createErr != nil {
+			return nil, createErr
+		}
+		log.Printf("recategorize: run %d (%s) failed: %v", run.ID, trigger, err)
+		return run, err
+	}
+
+	for _, l := range run.Logs {
+		log.Printf("recategorize: follow %d of user %d (%s) category %d -> %d by rule %s",
+			l.FollowID, l.UserID, l.AnimeName, l.FromCategory, l.ToCategory, l.Rule)
+	}
+	log.Printf("recategorize: run %d (%s) changed %d follows", run.ID, trigger, run.Changed)
+	return run, nil
+}
+
+// validateRules 检查规则使用的追番分类是否仍然存在
+func (s *Service) validateRules() error {
+	for _, rule := range s.rules {
+		for _, category := range []common.FollowCategory{rule.From, rule.To} {
+			if err := s.validate(category); err != nil {
+				return fmt.Errorf("rule %s: %w", rule.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// collectChanges 根据规则计算需要变更的追番，每个追番只命中第一条规则
+// 放送状态按 now 时刻在内存中推导，不写入数据库
+func (s *Service) collectChanges(userID *uint, now time.Time) ([]Change, error) {
+	var changes []Change
+	seen := make(map[uint]bool)
+	for _, rule := range s.rules {
+		follows, err := s.followDAO.GetByCategory(userID, rule.From)
+		if err != nil {
+			return nil, err
+		}
+		for _, follow := range follows {
+			if seen[follow.ID] || !slices.Contains(rule.AiringStatuses, animesrv.EffectiveAiringStatus(&follow.Anime, now)) {
+				continue
+			}
+			seen[follow.ID] = true
+			changes = append(changes, Change{
+				FollowID:     follow.ID,
+				UserID:       follow.UserID,
+				AnimeID:      follow.AnimeID,
+				AnimeName:    follow.Anime.Name,
+				Rule:         rule.Name,
+				FromCategory: rule.From,
+				ToCategory:   rule.To,
+			})
+		}
+	}
+	return changes, nil
+}
+
+// GetRuns 获取用户手动执行和定时执行的记录
+func (s *Service) GetRuns(userID uint, page, pageSize int) ([]models.RecategorizeRun, int64, error) {
+	return s.recategorizeDAO.GetRunsPaginated(userID, page, pageSize)
+}
+
+// GetRunByID 根据ID获取执行记录（只包含该用户追番的变更明细）
+func (s *Service) GetRunByID(userID, id uint) (*models.RecategorizeRun, error) {
+	return s.recategorizeDAO.GetRunByID(userID, id)
+}
+
+// Start 启动定时任务，ctx 取消时停止
+func (s *Service) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		log.Printf("recategorize: invalid interval %s, scheduler disabled", interval)
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.Run(TriggerSchedule, nil); err != nil {
+					log.Printf("recategorize: scheduled run failed: %v", err)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,217 @@
+package studio
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao"
+	"kong-anime-go/internal/dao/models"
+	"kong-anime-go/internal/i18n"
+)
+
+var (
+	// ErrMergeIntoSelf 不能将制作公司合并到自身
+	ErrMergeIntoSelf = errors.New("cannot merge studio into itself")
+	// ErrHasAnimes 制作公司有关联的动漫，不能删除
+	ErrHasAnimes = errors.New("cannot delete studio with related animes, merge it into another studio instead")
+)
+
+// ErrNameExists 名称或别名已经对应其他制作公司，可以改为合并到该制作公司
+type ErrNameExists struct {
+	Name     string
+	Existing *models.Studio
+}
+
+func (e *ErrNameExists) Error() string {
+	return e.Localize(i18n.En)
+}
+
+// Localize 返回 lang 中的错误信息
+func (e *ErrNameExists) Localize(lang i18n.Lang) string {
+	return i18n.Sprintf(lang, "%q already refers to studio %q (id %d), merge into it instead", e.Name, e.Existing.Name, e.Existing.ID)
+}
+
+// MergePreview 合并预览
+type MergePreview struct {
+	Source *models.Studio   `json:"source"`
+	Target *models.Studio   `json:"target"`
+	Impact *dao.MergeImpact `json:"impact"`
+}
+
+// Service 处理制作公司相关的服务
+type Service struct {
+	studioDAO *dao.StudioDAO
+	animeDAO  *dao.AnimeDAO
+}
+
+// NewService 创建一个新的 StudioService
+func NewService(studioDAO *dao.StudioDAO, animeDAO *dao.AnimeDAO) *Service {
+	return &Service{
+		studioDAO: studioDAO,
+		animeDAO:  animeDAO,
+	}
+}
+
+// Create 创建一个新的制作公司，名称或别名已存在时返回已有的制作公司
+// 名称是新的但别名已经对应其他制作公司时返回 ErrNameExists
+func (s *Service) Create(studio *models.Studio) (*models.Studio, error) {
+	existingStudio, err := s.studioDAO.FindByNameOrAlias(studio.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existingStudio != nil {
+		return existingStudio, nil
+	}
+	if err := checkNames(s.studioDAO, studio); err != nil {
+		return nil, err
+	}
+	if err := s.studioDAO.Create(studio); err != nil {
+		return nil, err
+	}
+	return studio, nil
+}
+
+// Delete 删除一个制作公司，有关联的动漫时需要先合并到其他制作公司
+func (s *Service) Delete(id uint) (uint, error) {
+	relatedItems, err := s.studioDAO.CheckRelatedItems(id)
+	if err != nil {
+		return 0, err
+	}
+	if relatedItems {
+		return 0, ErrHasAnimes
+	}
+	// 硬删除
+	if err := s.studioDAO.HardDelete(id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// Update 更新一个制作公司，名称或别名已经对应其他制作公司时返回 ErrNameExists
+func (s *Service) Update(studio *models.Studio) (*models.Studio, error) {
+	existingStudio, err := s.studioDAO.GetByID(studio.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNames(s.studioDAO, studio); err != nil {
+		return nil, err
+	}
+	existingStudio.Name = studio.Name
+	existingStudio.Aliases = studio.Aliases
+	if err := s.studioDAO.Update(existingStudio); err != nil {
+		return nil, err
+	}
+	return existingStudio, nil
+}
+
+// checkNames 检查制作公司的名称和别名是否已经对应其他制作公司
+func checkNames(studioDAO *dao.StudioDAO, studio *models.Studio) error {
+	for _, name := range append([]string{studio.Name}, common.SplitNames(studio.Aliases)...) {
+		other, err := studioDAO.FindByNameOrAlias(name)
+		if err != nil {
+			return err
+		}
+		if other != nil && other.ID != studio.ID {
+			return &ErrNameExists{Name: name, Existing: other}
+		}
+	}
+	return nil
+}
+
+// GetByID 根据ID获取制作公司
+func (s *Service) GetByID(id uint) (*models.Studio, error) {
+	return s.studioDAO.GetByID(id)
+}
+
+// GetAll 获取所有制作公司
+func (s *Service) GetAll() ([]models.Studio, error) {
+	return s.studioDAO.GetAll()
+}
+
+// GetByName 根据名称或别名获取制作公司
+func (s *Service) GetByName(name string) ([]models.Studio, error) {
+	return s.studioDAO.GetByNameLike(name)
+}
+
+// GetAnimes 获取制作公司参与制作的动漫
+func (s *Service) GetAnimes(id uint, page, pageSize int) ([]models.Anime, int64, error) {
+	if _, err := s.studioDAO.GetByID(id); err != nil {
+		return nil, 0, err
+	}
+	return s.animeDAO.GetByStudio(id, page, pageSize)
+}
+
+// GetStats 获取制作公司统计信息
+func (s *Service) GetStats(id uint) (*dao.StudioStats, error) {
+	if _, err := s.studioDAO.GetByID(id); err != nil {
+		return nil, err
+	}
+	return s.studioDAO.GetStudioStats(id)
+}
+
+// PreviewMerge 预览将源制作公司合并到目标制作公司时受影响的动漫数量
+func (s *Service) PreviewMerge(sourceID, targetID uint) (*MergePreview, error) {
+	return previewMerge(s.studioDAO, sourceID, targetID)
+}
+
+func previewMerge(studioDAO *dao.StudioDAO, sourceID, targetID uint) (*MergePreview, error) {
+	if sourceID == targetID {
+		return nil, ErrMergeIntoSelf
+	}
+	source, err := studioDAO.GetByID(sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("source studio: %w", err)
+	}
+	target, err := studioDAO.GetByID(targetID)
+	if err != nil {
+		return nil, fmt.Errorf("target studio: %w", err)
+	}
+	impact, err := studioDAO.GetMergeImpact(sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return &MergePreview{Source: source, Target: target, Impact: impact}, nil
+}
+
+// Merge 将源制作公司合并到目标制作公司：移动并去重源制作公司关联的动漫，将源制作公司的名称和别名转为目标制作公司的别名，
+// 然后删除源制作公司，所有操作在同一个事务中完成；之后按 Production 文本关联制作公司时，源制作公司的名称会解析到目标制作公司
+func (s *Service) Merge(sourceID, targetID uint) (*MergePreview, error) {
+	var preview *MergePreview
+	err := s.studioDAO.Transaction(func(txDAO *dao.StudioDAO) error {
+		var err error
+		preview, err = previewMerge(txDAO, sourceID, targetID)
+		if err != nil {
+			return err
+		}
+		if err := txDAO.MoveAnimes(sourceID, targetID); err != nil {
+			return err
+		}
+		// 先删除源制作公司，避免名称的唯一索引冲突
+		if err := txDAO.HardDelete(sourceID); err != nil {
+			return err
+		}
+		target := preview.Target
+		target.Aliases = mergeAliases(target, preview.Source)
+		return txDAO.Update(target)
+	})
+	return preview, err
+}
+
+// mergeAliases 将源制作公司的名称和别名并入目标制作公司的别名，忽略与已有名称写法相同的项
+func mergeAliases(target, source *models.Studio) string {
+	aliases := common.SplitNames(target.Aliases)
+	known := []string{common.CanonicalName(target.Name)}
+	for _, alias := range aliases {
+		known = append(known, common.CanonicalName(alias))
+	}
+	for _, name := range append([]string{source.Name}, common.SplitNames(source.Aliases)...) {
+		if key := common.CanonicalName(name); !slices.Contains(known, key) {
+			known = append(known, key)
+			aliases = append(aliases, name)
+		}
+	}
+	return strings.Join(aliases, ",")
+}
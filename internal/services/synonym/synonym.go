@@ -0,0 +1,154 @@
+package synonym
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao"
+	"kong-anime-go/internal/dao/models"
+	"kong-anime-go/internal/i18n"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidKind 同义词类型不合法
+	ErrInvalidKind = errors.New("invalid synonym kind, must be tag or category")
+	// ErrEmptyName 同义词为空
+	ErrEmptyName = errors.New("synonym name is required")
+	// ErrRedundant 同义词与目标名称规范化后相同，不需要登记
+	ErrRedundant = errors.New("synonym already resolves to the target by normalization")
+)
+
+// ErrConflict 同义词规范化后已经指向其他标签或分类
+type ErrConflict struct {
+	TargetID   uint
+	TargetName string
+}
+
+func (e *ErrConflict) Error() string {
+	return e.Localize(i18n.En)
+}
+
+// Localize 返回 lang 中的错误信息
+func (e *ErrConflict) Localize(lang i18n.Lang) string {
+	return i18n.Sprintf(lang, "synonym already resolves to %q (id %d), merge them instead", e.TargetName, e.TargetID)
+}
+
+// Resolution 名称的解析结果
+type Resolution struct {
+	Kind          common.SynonymKind `json:"kind"`
+	Name          string             `json:"name"`
+	CanonicalName string             `json:"canonical_name"`
+	TargetID      uint               `json:"target_id"`
+	TargetName    string             `json:"target_name"`
+}
+
+// Service 处理同义词相关的服务
+type Service struct {
+	synonymDAO  *dao.SynonymDAO
+	tagDAO      *dao.TagDAO
+	categoryDAO *dao.CategoryDAO
+}
+
+// NewService 创建一个新的 SynonymService
+func NewService(synonymDAO *dao.SynonymDAO, tagDAO *dao.TagDAO, categoryDAO *dao.CategoryDAO) *Service {
+	return &Service{
+		synonymDAO:  synonymDAO,
+		tagDAO:      tagDAO,
+		categoryDAO: categoryDAO,
+	}
+}
+
+// Create 登记同义词，将 name 的各种写法指向 targetID 指定的标签或分类
+func (s *Service) Create(kind common.SynonymKind, name string, targetID uint) (*models.Synonym, error) {
+	if !kind.IsValid() {
+		return nil, ErrInvalidKind
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrEmptyName
+	}
+	targetName, err := s.targetName(kind, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("target %s: %w", kind, err)
+	}
+	canonicalName := common.CanonicalName(name)
+	if canonicalName == common.CanonicalName(targetName) {
+		return nil, ErrRedundant
+	}
+
+	// 名称已经能解析到其他标签或分类时，登记同义词会让两者无法区分，应当合并
+	resolved, err := s.Resolve(kind, name)
+	if err == nil && resolved.TargetID != targetID {
+		return nil, &ErrConflict{TargetID: resolved.TargetID, TargetName: resolved.TargetName}
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if existing, err := s.synonymDAO.GetByCanonicalName(kind, canonicalName); err == nil {
+		return existing, nil
+	}
+
+	synonym := &models.Synonym{Kind: string(kind), Name: name, CanonicalName: canonicalName, TargetID: targetID}
+	if err := s.synonymDAO.Create(synonym); err != nil {
+		return nil, err
+	}
+	return synonym, nil
+}
+
+// Delete 删除同义词
+func (s *Service) Delete(id uint) (uint, error) {
+	if _, err := s.synonymDAO.GetByID(id); err != nil {
+		return 0, err
+	}
+	return id, s.synonymDAO.Delete(id)
+}
+
+// GetAll 获取同义词，kind 为空时返回所有类型，targetID 为 0 时不按目标过滤
+func (s *Service) GetAll(kind common.SynonymKind, targetID uint) ([]models.Synonym, error) {
+	if kind != "" && !kind.IsValid() {
+		return nil, ErrInvalidKind
+	}
+	return s.synonymDAO.GetAll(kind, targetID)
+}
+
+// Resolve 解析名称指向的标签或分类，与创建动漫时使用的规则相同，解析不到时返回 gorm.ErrRecordNotFound
+func (s *Service) Resolve(kind common.SynonymKind, name string) (*Resolution, error) {
+	resolution := &Resolution{Kind: kind, Name: name, CanonicalName: common.CanonicalName(name)}
+	switch kind {
+	case common.SynonymKindTag:
+		tag, err := s.tagDAO.GetByCanonicalName(name)
+		if err != nil {
+			return nil, err
+		}
+		resolution.TargetID, resolution.TargetName = tag.ID, tag.Name
+	case common.SynonymKindCategory:
+		category, err := s.categoryDAO.GetByCanonicalName(name)
+		if err != nil {
+			return nil, err
+		}
+		resolution.TargetID, resolution.TargetName = category.ID, category.Name
+	default:
+		return nil, ErrInvalidKind
+	}
+	return resolution, nil
+}
+
+// targetName 获取同义词指向的标签或分类的名称
+func (s *Service) targetName(kind common.SynonymKind, id uint) (string, error) {
+	if kind == common.SynonymKindTag {
+		tag, err := s.tagDAO.GetByID(id)
+		if err != nil {
+			return "", err
+		}
+		return tag.Name, nil
+	}
+	category, err := s.categoryDAO.GetByID(id)
+	if err != nil {
+		return "", err
+	}
+	return category.Name, nil
+}
@@ -0,0 +1,152 @@
+package tag
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"kong-anime-go/internal/common"
+	"kong-anime-go/internal/dao/models"
+	"kong-anime-go/internal/i18n"
+)
+
+var (
+	// ErrInvalidCardinality 标签组规则不合法
+	ErrInvalidCardinality = errors.New("invalid cardinality, must be exactly_one, at_most_one or many")
+	// ErrGroupNameExists 已存在同名的标签组
+	ErrGroupNameExists = errors.New("tag group already exists")
+)
+
+// GroupViolation 一部动漫在某个标签组中的标签数量不符合规则
+type GroupViolation struct {
+	GroupID     uint                  `json:"group_id"`
+	Group       string                `json:"group"`
+	Cardinality common.TagCardinality `json:"cardinality"`
+	Tags        []string              `json:"tags"` // 动漫在该组中的标签
+}
+
+func (v GroupViolation) String() string {
+	return v.Localize(i18n.En)
+}
+
+// Localize 返回 lang 中的违规说明
+func (v GroupViolation) Localize(lang i18n.Lang) string {
+	switch v.Cardinality {
+	case common.TagCardinalityExactlyOne:
+		if len(v.Tags) == 0 {
+			return i18n.Sprintf(lang, "tag group %q requires exactly one tag, got none", v.Group)
+		}
+		return i18n.Sprintf(lang, "tag group %q requires exactly one tag, got %d (%s)", v.Group, len(v.Tags), strings.Join(v.Tags, ", "))
+	default:
+		return i18n.Sprintf(lang, "tag group %q allows at most one tag, got %d (%s)", v.Group, len(v.Tags), strings.Join(v.Tags, ", "))
+	}
+}
+
+// ErrGroupViolation 动漫的标签不符合标签组规则
+type ErrGroupViolation struct {
+	Violations []GroupViolation
+}
+
+func (e *ErrGroupViolation) Error() string {
+	return e.Localize(i18n.En)
+}
+
+// Localize 返回 lang 中的错误信息
+func (e *ErrGroupViolation) Localize(lang i18n.Lang) string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.Localize(lang)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// CheckGroups 检查一部动漫的标签是否符合所有标签组的规则，tags 中不属于任何标签组的标签会被忽略
+func CheckGroups(groups []models.TagGroup, tags []models.Tag) []GroupViolation {
+	byGroup := make(map[uint][]string)
+	seen := make(map[uint]bool)
+	for _, tag := range tags {
+		if tag.GroupID == nil || seen[tag.ID] {
+			continue
+		}
+		seen[tag.ID] = true
+		byGroup[*tag.GroupID] = append(byGroup[*tag.GroupID], tag.Name)
+	}
+	var violations []GroupViolation
+	for _, group := range groups {
+		names := byGroup[group.ID]
+		if !group.Cardinality.Allows(len(names)) {
+			violations = append(violations, GroupViolation{
+				GroupID:     group.ID,
+				Group:       group.Name,
+				Cardinality: group.Cardinality,
+				Tags:        append([]string{}, names...),
+			})
+		}
+	}
+	return violations
+}
+
+// CreateGroup 创建一个新的标签组
+func (s *Service) CreateGroup(group *models.TagGroup) (*models.TagGroup, error) {
+	if !group.Cardinality.IsValid() {
+		return nil, ErrInvalidCardinality
+	}
+	if existing, err := s.tagDAO.GetGroupByName(group.Name); err == nil {
+		return nil, fmt.Errorf("%w: %q (id %d)", ErrGroupNameExists, existing.Name, existing.ID)
+	}
+	group.Tags = nil
+	if err := s.tagDAO.CreateGroup(group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// UpdateGroup 更新标签组的名称、规则和说明
+func (s *Service) UpdateGroup(group *models.TagGroup) (*models.TagGroup, error) {
+	if !group.Cardinality.IsValid() {
+		return nil, ErrInvalidCardinality
+	}
+	existing, err := s.tagDAO.GetGroupByID(group.ID)
+	if err != nil {
+		return nil, err
+	}
+	if other, err := s.tagDAO.GetGroupByName(group.Name); err == nil && other.ID != existing.ID {
+		return nil, fmt.Errorf("%w: %q (id %d)", ErrGroupNameExists, other.Name, other.ID)
+	}
+	existing.Name = group.Name
+	existing.Cardinality = group.Cardinality
+	existing.Description = group.Description
+	if err := s.tagDAO.UpdateGroup(existing); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// DeleteGroup 删除标签组，组内的标签保留但不再属于任何标签组
+func (s *Service) DeleteGroup(id uint) (uint, error) {
+	if _, err := s.tagDAO.GetGroupByID(id); err != nil {
+		return 0, err
+	}
+	return id, s.tagDAO.HardDeleteGroup(id)
+}
+
+// GetGroups 获取所有标签组及组内的标签
+func (s *Service) GetGroups() ([]models.TagGroup, error) {
+	return s.tagDAO.GetAllGroups()
+}
+
+// SetGroup 设置标签所属的标签组，groupID 为 nil 时移出标签组；已有动漫因此违反规则时不会阻止，可通过违规报告查看
+func (s *Service) SetGroup(id uint, groupID *uint) (*models.Tag, error) {
+	if _, err := s.tagDAO.GetByID(id); err != nil {
+		return nil, err
+	}
+	if groupID != nil {
+		if _, err := s.tagDAO.GetGroupByID(*groupID); err != nil {
+			return nil, fmt.Errorf("tag group: %w", err)
+		}
+	}
+	if err := s.tagDAO.SetGroup(id, groupID); err != nil {
+		return nil, err
+	}
+	return s.tagDAO.GetByID(id)
+}
@@ -2,10 +2,48 @@ package tag
 
 import (
 	"errors"
+	"fmt"
+	"kong-anime-go/internal/common"
 	"kong-anime-go/internal/dao"
 	"kong-anime-go/internal/dao/models"
+	"kong-anime-go/internal/i18n"
+	"slices"
 )
 
+var (
+	// ErrMergeIntoSelf 不能将标签合并到自身
+	ErrMergeIntoSelf = errors.New("cannot merge tag into itself")
+	// ErrParentCycle 父标签不能是自身或自身的子标签
+	ErrParentCycle = errors.New("tag cannot be placed under itself or its descendants")
+	// ErrTooDeep 标签层级超过上限
+	ErrTooDeep = i18n.Errorf("tag hierarchy cannot be deeper than %d levels", common.MaxHierarchyDepth)
+	// ErrInvalidColor 颜色格式不合法
+	ErrInvalidColor = errors.New("color must be in #RGB or #RRGGBB format")
+	// ErrInvalidOrder 排序列表中有不存在或重复的标签
+	ErrInvalidOrder = errors.New("order must list existing tags without duplicates")
+)
+
+// ErrNameExists 已存在同名的标签，可以改为合并到该标签
+type ErrNameExists struct {
+	Existing *models.Tag
+}
+
+func (e *ErrNameExists) Error() string {
+	return e.Localize(i18n.En)
+}
+
+// Localize 返回 lang 中的错误信息
+func (e *ErrNameExists) Localize(lang i18n.Lang) string {
+	return i18n.Sprintf(lang, "tag %q already exists (id %d), merge into it instead", e.Existing.Name, e.Existing.ID)
+}
+
+// MergePreview 合并预览
+type MergePreview struct {
+	Source *models.Tag      `json:"source"`
+	Target *models.Tag      `json:"target"`
+	Impact *dao.MergeImpact `json:"impact"`
+}
+
 // Service 处理标签相关的服务
 type Service struct {
 	tagDAO *dao.TagDAO
@@ -18,12 +56,18 @@ func NewService(tagDAO *dao.TagDAO) *Service {
 	}
 }
 
-// Create 创建一个新的标签
+// Create 创建一个新的标签，名称能解析到已有标签（包括同义词和不同写法）时返回已有标签
 func (s *Service) Create(tag *models.Tag) (*models.Tag, error) {
-	existingTag, err := s.tagDAO.GetByName(tag.Name)
+	existingTag, err := s.tagDAO.GetByCanonicalName(tag.Name)
 	if err == nil && existingTag != nil {
 		return existingTag, nil
 	}
+	if err := validateDisplay(tag); err != nil {
+		return nil, err
+	}
+	if err := validateParent(s.tagDAO, 0, tag.ParentID); err != nil {
+		return nil, err
+	}
 	if err := s.tagDAO.Create(tag); err != nil {
 		return nil, err
 	}
@@ -40,8 +84,21 @@ func (s *Service) Delete(id uint) (uint, error) {
 	if relatedItems {
 		return 0, errors.New("cannot delete tag with related items")
 	}
-	// 硬删除
-	if err := s.tagDAO.HardDelete(id); err != nil {
+	existingTag, err := s.tagDAO.GetByID(id)
+	if err != nil {
+		return 0, err
+	}
+	// 硬删除，同时删除指向该标签的同义词，子标签移动到该标签的父标签下
+	err = s.tagDAO.Transaction(func(txDAO *dao.TagDAO) error {
+		if err := txDAO.DeleteSynonyms(id); err != nil {
+			return err
+		}
+		if err := txDAO.ReparentChildren(id, existingTag.ParentID); err != nil {
+			return err
+		}
+		return txDAO.HardDelete(id)
+	})
+	if err != nil {
 		return 0, err
 	}
 	return id, nil
@@ -56,12 +113,20 @@ func (s *Service) Update(tag *models.Tag) (*models.Tag, error) {
 	if existingTag == nil {
 		return nil, errors.New("tag not found")
 	}
+	if err := validateDisplay(tag); err != nil {
+		return nil, err
+	}
 
-	if existingTag.Name == tag.Name {
-		return existingTag, nil
+	if existingTag.Name != tag.Name {
+		if other, err := s.tagDAO.GetByCanonicalName(tag.Name); err == nil && other.ID != existingTag.ID {
+			return nil, &ErrNameExists{Existing: other}
+		}
 	}
 
 	existingTag.Name = tag.Name
+	existingTag.Description = tag.Description
+	existingTag.Color = tag.Color
+	existingTag.Icon = tag.Icon
 	if err := s.tagDAO.Update(existingTag); err != nil {
 		return nil, err
 	}
@@ -78,12 +143,176 @@ func (s *Service) GetAll() ([]models.Tag, error) {
 	return s.tagDAO.GetAll()
 }
 
+// GetTree 获取所有标签的树形结构，返回顶层标签，子标签按显示顺序填充在 Children 中
+func (s *Service) GetTree() ([]models.Tag, error) {
+	tags, err := s.tagDAO.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	children := make(map[uint][]models.Tag)
+	for _, tag := range tags {
+		if tag.ParentID != nil {
+			children[*tag.ParentID] = append(children[*tag.ParentID], tag)
+		}
+	}
+	var build func(tag models.Tag) models.Tag
+	build = func(tag models.Tag) models.Tag {
+		for _, child := range children[tag.ID] {
+			tag.Children = append(tag.Children, build(child))
+		}
+		return tag
+	}
+	roots := make([]models.Tag, 0)
+	for _, tag := range tags {
+		if tag.ParentID == nil {
+			roots = append(roots, build(tag))
+		}
+	}
+	return roots, nil
+}
+
+// Reorder 按 ids 的顺序排列标签，未列出的标签保持原有顺序排在后面
+func (s *Service) Reorder(ids []uint) ([]models.Tag, error) {
+	tags, err := s.tagDAO.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	listed := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if listed[id] || !slices.ContainsFunc(tags, func(tag models.Tag) bool { return tag.ID == id }) {
+			return nil, fmt.Errorf("%w: %d", ErrInvalidOrder, id)
+		}
+		listed[id] = true
+	}
+	order := append([]uint{}, ids...)
+	for _, tag := range tags {
+		if !listed[tag.ID] {
+			order = append(order, tag.ID)
+		}
+	}
+	err = s.tagDAO.Transaction(func(txDAO *dao.TagDAO) error {
+		return txDAO.SetSortOrders(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.tagDAO.GetAll()
+}
+
+// validateDisplay 检查标签的显示设置
+func validateDisplay(tag *models.Tag) error {
+	if tag.Color != "" && !common.IsHexColor(tag.Color) {
+		return ErrInvalidColor
+	}
+	return nil
+}
+
+// SetParent 将标签移动到 parentID 下，parentID 为 nil 时成为顶层标签
+func (s *Service) SetParent(id uint, parentID *uint) (*models.Tag, error) {
+	tag, err := s.tagDAO.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateParent(s.tagDAO, id, parentID); err != nil {
+		return nil, err
+	}
+	tag.ParentID = parentID
+	if err := s.tagDAO.Update(tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// validateParent 检查将标签 id（新建时为 0）放到 parentID 下是否会形成环或超过层级上限
+func validateParent(tagDAO *dao.TagDAO, id uint, parentID *uint) error {
+	if parentID == nil {
+		return nil
+	}
+	if _, err := tagDAO.GetByID(*parentID); err != nil {
+		return fmt.Errorf("parent tag: %w", err)
+	}
+	h, err := tagDAO.GetHierarchy()
+	if err != nil {
+		return err
+	}
+	height := 1
+	if id != 0 {
+		if slices.Contains(h.Descendants(id), *parentID) {
+			return ErrParentCycle
+		}
+		height = h.Height(id)
+	}
+	if h.Depth(*parentID)+height > common.MaxHierarchyDepth {
+		return ErrTooDeep
+	}
+	return nil
+}
+
 // GetByName 根据名称获取标签
 func (s *Service) GetByName(name string) ([]models.Tag, error) {
 	return s.tagDAO.GetByNameLike(name)
 }
 
-// GetStats 获取标签统计信息
-func (s *Service) GetStats() (map[string]int, error) {
+// GetStats 按显示顺序获取标签统计信息
+func (s *Service) GetStats() ([]dao.DisplayStat, error) {
 	return s.tagDAO.GetTagStats()
 }
+
+// PreviewMerge 预览将源标签合并到目标标签时受影响的项目数量
+func (s *Service) PreviewMerge(sourceID, targetID uint) (*MergePreview, error) {
+	return previewMerge(s.tagDAO, sourceID, targetID)
+}
+
+func previewMerge(tagDAO *dao.TagDAO, sourceID, targetID uint) (*MergePreview, error) {
+	if sourceID == targetID {
+		return nil, ErrMergeIntoSelf
+	}
+	source, err := tagDAO.GetByID(sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("source tag: %w", err)
+	}
+	target, err := tagDAO.GetByID(targetID)
+	if err != nil {
+		return nil, fmt.Errorf("target tag: %w", err)
+	}
+	// 源标签的子标签合并后移动到目标标签下
+	h, err := tagDAO.GetHierarchy()
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(h.Descendants(sourceID), targetID) {
+		return nil, ErrParentCycle
+	}
+	if h.Depth(targetID)+h.Height(sourceID)-1 > common.MaxHierarchyDepth {
+		return nil, ErrTooDeep
+	}
+	impact, err := tagDAO.GetMergeImpact(sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return &MergePreview{Source: source, Target: target, Impact: impact}, nil
+}
+
+// Merge 将源标签合并到目标标签：移动并去重源标签关联的动漫和电影，将源标签的名称和同义词转为目标标签的同义词，
+// 将源标签的子标签移动到目标标签下，然后删除源标签，所有操作在同一个事务中完成
+func (s *Service) Merge(sourceID, targetID uint) (*MergePreview, error) {
+	var preview *MergePreview
+	err := s.tagDAO.Transaction(func(txDAO *dao.TagDAO) error {
+		var err error
+		preview, err = previewMerge(txDAO, sourceID, targetID)
+		if err != nil {
+			return err
+		}
+		if err := txDAO.MoveItems(sourceID, targetID); err != nil {
+			return err
+		}
+		if err := txDAO.MoveSynonyms(preview.Source, preview.Target); err != nil {
+			return err
+		}
+		if err := txDAO.ReparentChildren(sourceID, &targetID); err != nil {
+			return err
+		}
+		return txDAO.HardDelete(sourceID)
+	})
+	return preview, err
+}